@@ -1,130 +1,297 @@
 package logging
 
 type SystemModuleLogger struct {
-	level      *LogLevel
+	level      *levelVar // inherit follows the Logger's level
 	ModuleName string
 	NameColor  TextModifier
 	TextColor  TextModifier
 	logger     *Logger
+	parent     *SystemModuleLogger
+	hooks      *hookSet
+	fields     []Field
 }
 
+// NewSystemModuleLogger returns the module moduleName, creating it on the
+// root Logger if needed. Called on a child made with With, it returns a copy
+// of the module that adds the fields of the child.
 func (l *Logger) NewSystemModuleLogger(moduleName string, nameColor, textColor TextModifier) *SystemModuleLogger {
-	if l.systemModules == nil {
-		l.systemModules = make(map[string]*SystemModuleLogger)
-	}
+	l.modules.mu.Lock()
+	defer l.modules.mu.Unlock()
 	// Check if module already exists
-	if existing, ok := l.systemModules[moduleName]; ok {
-		return existing
+	if existing, ok := l.modules.systemModules[moduleName]; ok {
+		return l.handle(existing)
 	}
 
 	systemModuleLogger := &SystemModuleLogger{
-		level:      &l.level,
-		logger:     l,
+		level:      newLevelVar(inherit),
+		hooks:      &hookSet{},
+		logger:     l.root,
 		ModuleName: moduleName,
 		NameColor:  nameColor,
 		TextColor:  textColor,
 	}
-	l.systemModules[moduleName] = systemModuleLogger
-	return systemModuleLogger
+	if level, ok := l.modules.levels[moduleName]; ok {
+		systemModuleLogger.SetLogLevel(level)
+	}
+	l.modules.systemModules[moduleName] = systemModuleLogger
+	return l.handle(systemModuleLogger)
 }
 
-func (l *Logger) GetSystemModule(moduleName string) *SystemModuleLogger {
-	if l.systemModules == nil {
-		return nil
+// handle returns a registered module as seen from l. Children made with With
+// get a copy logging through them, so their fields are added.
+func (l *Logger) handle(sm *SystemModuleLogger) *SystemModuleLogger {
+	if sm == nil || l == l.root {
+		return sm
+	}
+	h := *sm
+	h.logger = l
+	return &h
+}
+
+// NewChild returns the module "<ModuleName>.<name>". It inherits the level of
+// sm until it sets its own, and starts with the colors of sm. Children are
+// registered on the Logger under their full path; fields added to sm with
+// With apply to the returned handle only, not to the registered child.
+func (sm *SystemModuleLogger) NewChild(name string) *SystemModuleLogger {
+	l := sm.logger
+	moduleName := sm.ModuleName + "." + name
+	l.modules.mu.Lock()
+	defer l.modules.mu.Unlock()
+	parent := l.modules.systemModules[sm.ModuleName]
+	if parent == nil {
+		parent = sm
+	}
+	// The fields sm adds to the registered module
+	extra := sm.fields[min(len(parent.fields), len(sm.fields)):]
+
+	child, ok := l.modules.systemModules[moduleName]
+	if !ok {
+		child = &SystemModuleLogger{
+			level:      newLevelVar(inherit),
+			hooks:      &hookSet{},
+			logger:     l.root,
+			parent:     parent,
+			ModuleName: moduleName,
+			NameColor:  parent.NameColor,
+			TextColor:  parent.TextColor,
+			fields:     joinFields(parent.fields),
+		}
+		if level, ok := l.modules.levels[moduleName]; ok {
+			child.SetLogLevel(level)
+		}
+		l.modules.systemModules[moduleName] = child
+	}
+	h := l.handle(child)
+	if len(extra) > 0 {
+		h = h.With(extra...)
 	}
-	return l.systemModules[moduleName]
+	return h
+}
+
+// Parent returns the module NewChild was called on, nil for top level modules.
+func (sm *SystemModuleLogger) Parent() *SystemModuleLogger {
+	return sm.parent
+}
+
+func (l *Logger) GetSystemModule(moduleName string) *SystemModuleLogger {
+	l.modules.mu.RLock()
+	defer l.modules.mu.RUnlock()
+	return l.handle(l.modules.systemModules[moduleName])
+}
+
+// With returns a copy of the module logger that adds fields to every entry.
+// The copy is not registered on the Logger but shares its level and hooks.
+func (sm *SystemModuleLogger) With(fields ...Field) *SystemModuleLogger {
+	child := *sm
+	child.fields = joinFields(sm.fields, fields)
+	return &child
 }
 
 // Set LogLevel of the SystemModuleLogger
-// set logLevel to -1 for inherit LogLevel of logger
+// set logLevel to -1 for inherit LogLevel of the parent module or logger
 func (sm *SystemModuleLogger) SetLogLevel(logLevel LogLevel) {
-	if logLevel == -1 {
-		sm.level = &sm.logger.level
+	if logLevel == inherit {
+		sm.level.store(inherit)
+		return
 	}
-	newLevel := max(min(logLevel, NONE), DEBUG)
-	sm.level = &newLevel
+	sm.level.store(clampLevel(logLevel))
 }
 func (sm *SystemModuleLogger) ResetLogLevel() {
-	sm.level = &sm.logger.level
+	sm.level.store(inherit)
 }
 
+// GetLogLevel returns the level of the module. A module without its own
+// level inherits it from its parent module or the Logger.
 func (sm *SystemModuleLogger) GetLogLevel() LogLevel {
-	return *sm.level
+	if level := sm.level.load(); level != inherit {
+		return level
+	}
+	if sm.parent != nil {
+		return sm.parent.GetLogLevel()
+	}
+	return sm.logger.level.load()
+}
+
+// LevelOverridden reports whether the module has its own level instead of
+// inheriting one.
+func (sm *SystemModuleLogger) LevelOverridden() bool {
+	return sm.level.load() != inherit
+}
+
+// Trace level log with gray color
+func (sm *SystemModuleLogger) Trace(msg ...string) {
+	if sm.logger.enabled(TRACE, sm) {
+		sm.logger.logWithLevel(TRACE, sm, msg...)
+	}
 }
 
 // Debug level log with blue color
 func (sm *SystemModuleLogger) Debug(msg ...string) {
-	if *sm.level <= DEBUG {
-		sm.logger.logWithLevel(Blue, "DEBUG", sm, "", msg...)
+	if sm.logger.enabled(DEBUG, sm) {
+		sm.logger.logWithLevel(DEBUG, sm, msg...)
 	}
 }
 
 // Info level log with green color
 func (sm *SystemModuleLogger) Info(msg ...string) {
-	if *sm.level <= INFO {
-		sm.logger.logWithLevel(Green, "INFO", sm, "", msg...)
+	if sm.logger.enabled(INFO, sm) {
+		sm.logger.logWithLevel(INFO, sm, msg...)
+	}
+}
+
+// Notice level log with cyan color
+func (sm *SystemModuleLogger) Notice(msg ...string) {
+	if sm.logger.enabled(NOTICE, sm) {
+		sm.logger.logWithLevel(NOTICE, sm, msg...)
 	}
 }
 
 // Warn level log with yellow color
 func (sm *SystemModuleLogger) Warn(msg ...string) {
-	if *sm.level <= WARN {
-		sm.logger.logWithLevel(Yellow, "WARN", sm, "", msg...)
+	if sm.logger.enabled(WARN, sm) {
+		sm.logger.logWithLevel(WARN, sm, msg...)
 	}
 }
 
 // Error level log with red color
 func (sm *SystemModuleLogger) Error(msg ...string) {
-	if *sm.level <= ERROR {
-		sm.logger.logWithLevel(Red, "ERROR", sm, "", msg...)
+	if sm.logger.enabled(ERROR, sm) {
+		sm.logger.logWithLevel(ERROR, sm, msg...)
 	}
 }
 
 // Error level log with red color
 func (sm *SystemModuleLogger) Fail(msg ...string) {
-	if *sm.level <= FAIL {
-		sm.logger.logWithLevel(Red+MagentaBG, "FAIL", sm, Red+MagentaBG, msg...)
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevel(FAIL, sm, msg...)
+	}
+}
+
+// Trace level log with gray color
+func (sm *SystemModuleLogger) TraceF(format string, v ...any) {
+	if sm.logger.enabled(TRACE, sm) {
+		sm.logger.logWithLevelF(TRACE, sm, format, v...)
 	}
 }
 
 // Debug level log with blue color
 func (sm *SystemModuleLogger) DebugF(format string, v ...any) {
-	if *sm.level <= DEBUG {
-		sm.logger.logWithLevelF(Blue, "DEBUG", sm, "", format, v...)
+	if sm.logger.enabled(DEBUG, sm) {
+		sm.logger.logWithLevelF(DEBUG, sm, format, v...)
 	}
 }
 
 // Info level log with green color
 func (sm *SystemModuleLogger) InfoF(format string, v ...any) {
-	if *sm.level <= INFO {
-		sm.logger.logWithLevelF(Green, "INFO", sm, "", format, v...)
+	if sm.logger.enabled(INFO, sm) {
+		sm.logger.logWithLevelF(INFO, sm, format, v...)
+	}
+}
+
+// Notice level log with cyan color
+func (sm *SystemModuleLogger) NoticeF(format string, v ...any) {
+	if sm.logger.enabled(NOTICE, sm) {
+		sm.logger.logWithLevelF(NOTICE, sm, format, v...)
 	}
 }
 
 // Warn level log with yellow color
 func (sm *SystemModuleLogger) WarnF(format string, v ...any) {
-	if *sm.level <= WARN {
-		sm.logger.logWithLevelF(Yellow, "WARN", sm, "", format, v...)
+	if sm.logger.enabled(WARN, sm) {
+		sm.logger.logWithLevelF(WARN, sm, format, v...)
 	}
 }
 
 // Error level log with red color
 func (sm *SystemModuleLogger) ErrorF(format string, v ...any) {
-	if *sm.level <= ERROR {
-		sm.logger.logWithLevelF(Red, "ERROR", sm, "", format, v...)
+	if sm.logger.enabled(ERROR, sm) {
+		sm.logger.logWithLevelF(ERROR, sm, format, v...)
 	}
 }
 
 // Error level log with red color
 func (sm *SystemModuleLogger) FailF(format string, v ...any) {
-	if *sm.level <= FAIL {
-		sm.logger.logWithLevelF(Red+MagentaBG, "FAIL", sm, Red+MagentaBG, format, v...)
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevelF(FAIL, sm, format, v...)
+	}
+}
+
+// Trace level log with fields
+func (sm *SystemModuleLogger) TraceW(msg string, fields ...Field) {
+	if sm.logger.enabled(TRACE, sm) {
+		sm.logger.logWithLevelW(TRACE, sm, msg, fields...)
 	}
 }
+
+// Debug level log with fields
+func (sm *SystemModuleLogger) DebugW(msg string, fields ...Field) {
+	if sm.logger.enabled(DEBUG, sm) {
+		sm.logger.logWithLevelW(DEBUG, sm, msg, fields...)
+	}
+}
+
+// Info level log with fields
+func (sm *SystemModuleLogger) InfoW(msg string, fields ...Field) {
+	if sm.logger.enabled(INFO, sm) {
+		sm.logger.logWithLevelW(INFO, sm, msg, fields...)
+	}
+}
+
+// Notice level log with fields
+func (sm *SystemModuleLogger) NoticeW(msg string, fields ...Field) {
+	if sm.logger.enabled(NOTICE, sm) {
+		sm.logger.logWithLevelW(NOTICE, sm, msg, fields...)
+	}
+}
+
+// Warn level log with fields
+func (sm *SystemModuleLogger) WarnW(msg string, fields ...Field) {
+	if sm.logger.enabled(WARN, sm) {
+		sm.logger.logWithLevelW(WARN, sm, msg, fields...)
+	}
+}
+
+// Error level log with fields
+func (sm *SystemModuleLogger) ErrorW(msg string, fields ...Field) {
+	if sm.logger.enabled(ERROR, sm) {
+		sm.logger.logWithLevelW(ERROR, sm, msg, fields...)
+	}
+}
+
+// Fail level log with fields
+func (sm *SystemModuleLogger) FailW(msg string, fields ...Field) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevelW(FAIL, sm, msg, fields...)
+	}
+}
+
 func (sm *SystemModuleLogger) Printf(format string, v ...any) {
-	sm.logger.logWithLevelF("", "????", sm, "", format, v...)
+	sm.logger.logWithLevelF(NONE, sm, format, v...)
 }
 
+// Println logs at DEBUG level
 func (sm *SystemModuleLogger) Println(msg ...string) {
-	sm.Debug(msg...)
+	if sm.logger.enabled(DEBUG, sm) {
+		sm.logger.logWithLevel(DEBUG, sm, msg...)
+	}
 }
@@ -0,0 +1,252 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what an asynchronous Logger does when its queue is
+// full.
+type OverflowPolicy int
+
+const (
+	// Block waits until the queue has room
+	Block OverflowPolicy = iota
+	// DropNewest discards the entry being logged
+	DropNewest
+	// DropOldest discards the oldest queued entry to make room
+	DropOldest
+	// DropBelowLevel discards entries below AsyncOptions.DropLevel and blocks
+	// for the others
+	DropBelowLevel
+)
+
+// AsyncOptions configures asynchronous output.
+type AsyncOptions struct {
+	// Size is the number of entries the queue holds, defaults to 1024.
+	Size int
+	// Overflow is applied when the queue is full.
+	Overflow OverflowPolicy
+	// DropLevel is used with DropBelowLevel.
+	DropLevel LogLevel
+	// ReportInterval is how often the number of dropped entries is logged
+	// as a warning, defaults to 10 seconds. A negative value disables it.
+	ReportInterval time.Duration
+}
+
+type queuedEntry struct {
+	logger *Logger
+	entry  *Entry
+}
+
+// asyncQueue is a bounded ring buffer drained by a single worker.
+type asyncQueue struct {
+	options AsyncOptions
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    []queuedEntry
+	head   int
+	count  int
+	busy   bool // the worker is writing an entry
+	closed bool
+
+	dropped  atomic.Uint64
+	reportMu sync.Mutex
+	reported uint64 // dropped entries already reported
+
+	done       chan struct{}
+	stopReport chan struct{}
+}
+
+// EnableAsync makes the Logger queue entries and write them from a
+// background goroutine, so slow outputs do not block the caller. Use Flush
+// and Close to drain the queue. Calling it again drains the current queue
+// and replaces it.
+func (l *Logger) EnableAsync(options AsyncOptions) {
+	if options.Size <= 0 {
+		options.Size = 1024
+	}
+	if options.ReportInterval == 0 {
+		options.ReportInterval = 10 * time.Second
+	}
+	q := &asyncQueue{
+		options:    options,
+		buf:        make([]queuedEntry, options.Size),
+		done:       make(chan struct{}),
+		stopReport: make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	old := l.async.Swap(q)
+	go q.run()
+	if options.ReportInterval > 0 {
+		go q.reportLoop(l)
+	}
+	if old != nil {
+		old.close()
+		<-old.done
+		if old.options.ReportInterval > 0 {
+			old.report(l)
+		}
+	}
+}
+
+// enqueue queues an entry. It returns false when the queue is closed and
+// the entry has to be written synchronously.
+func (q *asyncQueue) enqueue(l *Logger, e *Entry) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.count == len(q.buf) && !q.closed {
+		switch q.options.Overflow {
+		case DropNewest:
+			q.dropped.Add(1)
+			return true
+		case DropOldest:
+			q.buf[q.head] = queuedEntry{}
+			q.head = (q.head + 1) % len(q.buf)
+			q.count--
+			q.dropped.Add(1)
+		case DropBelowLevel:
+			if e.Level.Severity() < q.options.DropLevel.Severity() {
+				q.dropped.Add(1)
+				return true
+			}
+			q.cond.Wait()
+		default:
+			q.cond.Wait()
+		}
+	}
+	if q.closed {
+		return false
+	}
+	q.buf[(q.head+q.count)%len(q.buf)] = queuedEntry{logger: l, entry: e}
+	q.count++
+	q.cond.Broadcast()
+	return true
+}
+
+// run writes queued entries until the queue is closed and empty.
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		for q.count == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.count == 0 {
+			q.mu.Unlock()
+			return
+		}
+		item := q.buf[q.head]
+		q.buf[q.head] = queuedEntry{}
+		q.head = (q.head + 1) % len(q.buf)
+		q.count--
+		q.busy = true
+		q.cond.Broadcast()
+		q.mu.Unlock()
+
+		item.logger.writeNow(item.entry)
+
+		q.mu.Lock()
+		q.busy = false
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+}
+
+// close stops the queue from taking entries and ends the worker once the
+// queued ones are written.
+func (q *asyncQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.stopReport)
+	}
+	q.cond.Broadcast()
+}
+
+// flush waits until every queued entry has been written.
+func (q *asyncQueue) flush() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.count > 0 || q.busy {
+		q.cond.Wait()
+	}
+}
+
+// reportLoop periodically logs how many entries were dropped.
+func (q *asyncQueue) reportLoop(l *Logger) {
+	ticker := time.NewTicker(q.options.ReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			q.report(l)
+		case <-q.stopReport:
+			return
+		}
+	}
+}
+
+// report logs the entries dropped since the last report, bypassing the
+// queue.
+func (q *asyncQueue) report(l *Logger) {
+	q.reportMu.Lock()
+	defer q.reportMu.Unlock()
+	total := q.dropped.Load()
+	if total == q.reported {
+		return
+	}
+	n := total - q.reported
+	q.reported = total
+	e := l.newEntry(WARN, nil, "dropped log entries, async queue was full", []Field{Any("dropped", n)})
+	l.writeNow(e)
+}
+
+// Flush writes the pending repeat counts of EnableDedup and blocks until all
+// queued entries are written.
+func (l *Logger) Flush() {
+	if dedup := l.dedup.Load(); dedup != nil {
+		dedup.flush(l)
+	}
+	if q := l.async.Load(); q != nil {
+		q.flush()
+	}
+}
+
+// Close writes the pending repeat counts of EnableDedup, drains the queue
+// and stops the background goroutine. Entries logged afterwards are written
+// synchronously. It returns ctx.Err() if ctx ends
+// before the queue is drained.
+func (l *Logger) Close(ctx context.Context) error {
+	if dedup := l.dedup.Load(); dedup != nil {
+		dedup.flush(l)
+	}
+	q := l.async.Load()
+	if q == nil {
+		return nil
+	}
+	q.close()
+
+	select {
+	case <-q.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if q.options.ReportInterval > 0 {
+		q.report(l)
+	}
+	return nil
+}
+
+// Dropped returns the number of entries the asynchronous queue discarded.
+func (l *Logger) Dropped() uint64 {
+	q := l.async.Load()
+	if q == nil {
+		return 0
+	}
+	return q.dropped.Load()
+}
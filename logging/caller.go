@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// EnableCaller annotates every entry with the file, line and function of
+// the call site.
+func (l *Logger) EnableCaller() {
+	l.caller = true
+}
+
+// EnableStacktrace attaches the stack of the calling goroutine to entries at
+// level and above, e.g. EnableStacktrace(ERROR).
+func (l *Logger) EnableStacktrace(level LogLevel) {
+	l.stacktrace = true
+	l.stackLevel = level
+}
+
+// needsCallers reports whether output has to walk the stack for an entry.
+func (l *Logger) needsCallers(level LogLevel) (pc bool, stack bool) {
+	stack = l.stacktrace && level.Severity() >= l.stackLevel.Severity() && level != NONE
+	return stack || l.caller || l.limiter.Load() != nil || l.handler != nil || l.logsFile(), stack
+}
+
+// annotate sets PC, Caller and Stack of an entry from the program counters
+// of its call stack, starting at the call site.
+func (l *Logger) annotate(e *Entry, pcs []uintptr, stack bool) {
+	if len(pcs) == 0 {
+		return
+	}
+	e.PC = pcs[0]
+	if l.caller {
+		e.Caller, _ = runtime.CallersFrames(pcs[:1]).Next()
+	}
+	if stack {
+		e.Stack = formatStack(pcs)
+	}
+}
+
+// callers returns the program counters of the call stack, omitting skip
+// frames starting at the caller of callers. Only the first is returned
+// unless all is set.
+func callers(skip int, all bool) []uintptr {
+	if !all {
+		var pcs [1]uintptr
+		n := runtime.Callers(skip+2, pcs[:])
+		return pcs[:n]
+	}
+	pcs := make([]uintptr, 32)
+	for {
+		n := runtime.Callers(skip+2, pcs)
+		if n < len(pcs) {
+			return pcs[:n]
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+}
+
+// formatStack renders frames in the layout of a goroutine trace:
+//
+//	main.handle
+//		/src/app/main.go:42
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		b.WriteString(frame.Function)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// ShortCaller returns the call site as "dir/file.go:line", or "" when the
+// entry has no caller.
+func (e *Entry) ShortCaller() string {
+	if e.Caller.File == "" {
+		return ""
+	}
+	dir, file := filepath.Split(e.Caller.File)
+	return filepath.Base(dir) + "/" + file + ":" + strconv.Itoa(e.Caller.Line)
+}
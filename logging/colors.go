@@ -1,5 +1,12 @@
 package logging
 
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
 type TextModifier string
 
 // Text reset / modifiers
@@ -56,3 +63,201 @@ const (
 	BrightCyanBG    TextModifier = "\033[106m"
 	BrightWhiteBG   TextModifier = "\033[107m"
 )
+
+// Color256 is a foreground color from the 256-color palette
+func Color256(n uint8) TextModifier {
+	return TextModifier("\033[38;5;" + strconv.Itoa(int(n)) + "m")
+}
+
+// BG256 is a background color from the 256-color palette
+func BG256(n uint8) TextModifier {
+	return TextModifier("\033[48;5;" + strconv.Itoa(int(n)) + "m")
+}
+
+// RGB is a 24-bit foreground color
+func RGB(r, g, b uint8) TextModifier {
+	return TextModifier(fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b))
+}
+
+// BgRGB is a 24-bit background color
+func BgRGB(r, g, b uint8) TextModifier {
+	return TextModifier(fmt.Sprintf("\033[48;2;%d;%d;%dm", r, g, b))
+}
+
+// ColorDepth is the number of colors a terminal can show.
+type ColorDepth int
+
+const (
+	// TrueColor shows 24-bit colors, modifiers are used unchanged
+	TrueColor ColorDepth = iota
+	// Colors256 converts 24-bit colors to the 256-color palette
+	Colors256
+	// Colors16 converts 256-color and 24-bit colors to the 16 basic colors
+	Colors16
+)
+
+// DetectColorDepth reads the color depth of the terminal from FORCE_COLOR
+// ("1", "2" or "3"), COLORTERM and TERM.
+func DetectColorDepth() ColorDepth {
+	switch os.Getenv("FORCE_COLOR") {
+	case "1":
+		return Colors16
+	case "2":
+		return Colors256
+	case "3":
+		return TrueColor
+	}
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return Colors256
+	}
+	return Colors16
+}
+
+// Downsample converts the 256-color and 24-bit colors in m to the nearest
+// colors available at depth. Other modifiers are kept.
+func (m TextModifier) Downsample(depth ColorDepth) TextModifier {
+	if depth == TrueColor || !strings.Contains(string(m), "8;") {
+		return m
+	}
+	var b strings.Builder
+	s := string(m)
+	for {
+		start := strings.Index(s, "\033[")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], 'm')
+		if end < 0 {
+			break
+		}
+		b.WriteString(s[:start])
+		b.WriteString("\033[")
+		b.WriteString(downsampleParams(s[start+2:start+end], depth))
+		b.WriteByte('m')
+		s = s[start+end+1:]
+	}
+	b.WriteString(s)
+	return TextModifier(b.String())
+}
+
+// downsampleParams converts the extended colors in the parameters of one SGR
+// sequence, e.g. "1;38;2;255;128;0".
+func downsampleParams(params string, depth ColorDepth) string {
+	in := strings.Split(params, ";")
+	out := make([]string, 0, len(in))
+	for i := 0; i < len(in); i++ {
+		if (in[i] != "38" && in[i] != "48") || i+1 >= len(in) {
+			out = append(out, in[i])
+			continue
+		}
+		background := in[i] == "48"
+		switch {
+		case in[i+1] == "5" && i+2 < len(in):
+			n, err := strconv.Atoi(in[i+2])
+			if err != nil || depth != Colors16 {
+				out = append(out, in[i:i+3]...)
+			} else {
+				out = append(out, basicColorParam(nearestBasic(palette256(n)), background))
+			}
+			i += 2
+		case in[i+1] == "2" && i+4 < len(in):
+			r, _ := strconv.Atoi(in[i+2])
+			g, _ := strconv.Atoi(in[i+3])
+			bl, _ := strconv.Atoi(in[i+4])
+			c := [3]int{r, g, bl}
+			if depth == Colors256 {
+				out = append(out, in[i], "5", strconv.Itoa(nearest256(c)))
+			} else {
+				out = append(out, basicColorParam(nearestBasic(c), background))
+			}
+			i += 4
+		default:
+			out = append(out, in[i])
+		}
+	}
+	return strings.Join(out, ";")
+}
+
+// basicPalette holds the xterm defaults of the 16 basic colors.
+var basicPalette = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube of the
+// 256-color palette.
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+func colorDistance(a, b [3]int) int {
+	dr, dg, db := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dr*dr + dg*dg + db*db
+}
+
+// palette256 returns the RGB value of a 256-color palette entry.
+func palette256(n int) [3]int {
+	switch {
+	case n < 16:
+		return basicPalette[max(n, 0)]
+	case n < 232:
+		n -= 16
+		return [3]int{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+	default:
+		gray := 8 + 10*(min(n, 255)-232)
+		return [3]int{gray, gray, gray}
+	}
+}
+
+// nearest256 returns the 256-color palette entry closest to c, from the
+// color cube or the gray ramp.
+func nearest256(c [3]int) int {
+	n := 16
+	for i := 0; i < 3; i++ {
+		best := 0
+		for j, level := range cubeLevels {
+			if abs(level-c[i]) < abs(cubeLevels[best]-c[i]) {
+				best = j
+			}
+		}
+		n += best * []int{36, 6, 1}[i]
+	}
+	gray := min(max((c[0]+c[1]+c[2])/3-8+5, 0)/10, 23)
+	if colorDistance(palette256(232+gray), c) < colorDistance(palette256(n), c) {
+		return 232 + gray
+	}
+	return n
+}
+
+func nearestBasic(c [3]int) int {
+	best := 0
+	for i, p := range basicPalette {
+		if colorDistance(p, c) < colorDistance(basicPalette[best], c) {
+			best = i
+		}
+	}
+	return best
+}
+
+// basicColorParam returns the SGR parameter of a basic color.
+func basicColorParam(n int, background bool) string {
+	code := 30 + n
+	if n >= 8 {
+		code = 90 + n - 8
+	}
+	if background {
+		code += 10
+	}
+	return strconv.Itoa(code)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+package logging
+
+import "context"
+
+// TraceIDKey is the field key the trace ID of a context is logged under.
+const TraceIDKey = "trace_id"
+
+// SpanIDKey is the field key the span ID of a context is logged under.
+const SpanIDKey = "span_id"
+
+type contextKey struct{}
+
+// contextValues is stored in a context by ContextWithTraceID and
+// ContextWithFields.
+type contextValues struct {
+	traceID string
+	spanID  string
+	fields  []Field
+}
+
+func valuesFrom(ctx context.Context) contextValues {
+	if ctx == nil {
+		return contextValues{}
+	}
+	v, _ := ctx.Value(contextKey{}).(contextValues)
+	return v
+}
+
+// ContextWithTraceID returns a context carrying traceID. Entries logged with
+// the Ctx variants include it as the field "trace_id".
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	v := valuesFrom(ctx)
+	v.traceID = traceID
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// TraceIDFromContext returns the trace ID stored in ctx, or "".
+func TraceIDFromContext(ctx context.Context) string {
+	return valuesFrom(ctx).traceID
+}
+
+// ContextWithSpanID returns a context carrying spanID. Entries logged with
+// the Ctx variants include it as the field "span_id".
+func ContextWithSpanID(ctx context.Context, spanID string) context.Context {
+	v := valuesFrom(ctx)
+	v.spanID = spanID
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// SpanIDFromContext returns the span ID stored in ctx, or "".
+func SpanIDFromContext(ctx context.Context) string {
+	return valuesFrom(ctx).spanID
+}
+
+// ContextWithFields returns a context carrying fields in addition to the
+// fields already stored in ctx.
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	v := valuesFrom(ctx)
+	v.fields = joinFields(v.fields, fields)
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FieldsFromContext returns the trace ID, span ID and fields stored in ctx.
+func FieldsFromContext(ctx context.Context) []Field {
+	v := valuesFrom(ctx)
+	var ids []Field
+	if v.traceID != "" {
+		ids = append(ids, String(TraceIDKey, v.traceID))
+	}
+	if v.spanID != "" {
+		ids = append(ids, String(SpanIDKey, v.spanID))
+	}
+	if ids == nil {
+		return v.fields
+	}
+	return joinFields(ids, v.fields)
+}
@@ -0,0 +1,297 @@
+package logging
+
+import "context"
+
+// Trace level log with the trace ID and fields of ctx
+func (l *Logger) TraceCtx(ctx context.Context, msg ...string) {
+	if l.enabled(TRACE, nil) {
+		l.logWithLevelCtx(ctx, TRACE, nil, msg...)
+	}
+}
+
+// Debug level log with the trace ID and fields of ctx
+func (l *Logger) DebugCtx(ctx context.Context, msg ...string) {
+	if l.enabled(DEBUG, nil) {
+		l.logWithLevelCtx(ctx, DEBUG, nil, msg...)
+	}
+}
+
+// Info level log with the trace ID and fields of ctx
+func (l *Logger) InfoCtx(ctx context.Context, msg ...string) {
+	if l.enabled(INFO, nil) {
+		l.logWithLevelCtx(ctx, INFO, nil, msg...)
+	}
+}
+
+// Notice level log with the trace ID and fields of ctx
+func (l *Logger) NoticeCtx(ctx context.Context, msg ...string) {
+	if l.enabled(NOTICE, nil) {
+		l.logWithLevelCtx(ctx, NOTICE, nil, msg...)
+	}
+}
+
+// Warn level log with the trace ID and fields of ctx
+func (l *Logger) WarnCtx(ctx context.Context, msg ...string) {
+	if l.enabled(WARN, nil) {
+		l.logWithLevelCtx(ctx, WARN, nil, msg...)
+	}
+}
+
+// Error level log with the trace ID and fields of ctx
+func (l *Logger) ErrorCtx(ctx context.Context, msg ...string) {
+	if l.enabled(ERROR, nil) {
+		l.logWithLevelCtx(ctx, ERROR, nil, msg...)
+	}
+}
+
+// Fail level log with the trace ID and fields of ctx
+func (l *Logger) FailCtx(ctx context.Context, msg ...string) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevelCtx(ctx, FAIL, nil, msg...)
+	}
+}
+
+// Trace level log with the trace ID and fields of ctx
+func (l *Logger) TraceFCtx(ctx context.Context, format string, v ...any) {
+	if l.enabled(TRACE, nil) {
+		l.logWithLevelFCtx(ctx, TRACE, nil, format, v...)
+	}
+}
+
+// Debug level log with the trace ID and fields of ctx
+func (l *Logger) DebugFCtx(ctx context.Context, format string, v ...any) {
+	if l.enabled(DEBUG, nil) {
+		l.logWithLevelFCtx(ctx, DEBUG, nil, format, v...)
+	}
+}
+
+// Info level log with the trace ID and fields of ctx
+func (l *Logger) InfoFCtx(ctx context.Context, format string, v ...any) {
+	if l.enabled(INFO, nil) {
+		l.logWithLevelFCtx(ctx, INFO, nil, format, v...)
+	}
+}
+
+// Notice level log with the trace ID and fields of ctx
+func (l *Logger) NoticeFCtx(ctx context.Context, format string, v ...any) {
+	if l.enabled(NOTICE, nil) {
+		l.logWithLevelFCtx(ctx, NOTICE, nil, format, v...)
+	}
+}
+
+// Warn level log with the trace ID and fields of ctx
+func (l *Logger) WarnFCtx(ctx context.Context, format string, v ...any) {
+	if l.enabled(WARN, nil) {
+		l.logWithLevelFCtx(ctx, WARN, nil, format, v...)
+	}
+}
+
+// Error level log with the trace ID and fields of ctx
+func (l *Logger) ErrorFCtx(ctx context.Context, format string, v ...any) {
+	if l.enabled(ERROR, nil) {
+		l.logWithLevelFCtx(ctx, ERROR, nil, format, v...)
+	}
+}
+
+// Fail level log with the trace ID and fields of ctx
+func (l *Logger) FailFCtx(ctx context.Context, format string, v ...any) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevelFCtx(ctx, FAIL, nil, format, v...)
+	}
+}
+
+// Trace level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) TraceCtx(ctx context.Context, msg ...string) {
+	if sm.logger.enabled(TRACE, sm) {
+		sm.logger.logWithLevelCtx(ctx, TRACE, sm, msg...)
+	}
+}
+
+// Debug level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) DebugCtx(ctx context.Context, msg ...string) {
+	if sm.logger.enabled(DEBUG, sm) {
+		sm.logger.logWithLevelCtx(ctx, DEBUG, sm, msg...)
+	}
+}
+
+// Info level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) InfoCtx(ctx context.Context, msg ...string) {
+	if sm.logger.enabled(INFO, sm) {
+		sm.logger.logWithLevelCtx(ctx, INFO, sm, msg...)
+	}
+}
+
+// Notice level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) NoticeCtx(ctx context.Context, msg ...string) {
+	if sm.logger.enabled(NOTICE, sm) {
+		sm.logger.logWithLevelCtx(ctx, NOTICE, sm, msg...)
+	}
+}
+
+// Warn level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) WarnCtx(ctx context.Context, msg ...string) {
+	if sm.logger.enabled(WARN, sm) {
+		sm.logger.logWithLevelCtx(ctx, WARN, sm, msg...)
+	}
+}
+
+// Error level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) ErrorCtx(ctx context.Context, msg ...string) {
+	if sm.logger.enabled(ERROR, sm) {
+		sm.logger.logWithLevelCtx(ctx, ERROR, sm, msg...)
+	}
+}
+
+// Fail level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) FailCtx(ctx context.Context, msg ...string) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevelCtx(ctx, FAIL, sm, msg...)
+	}
+}
+
+// Trace level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) TraceFCtx(ctx context.Context, format string, v ...any) {
+	if sm.logger.enabled(TRACE, sm) {
+		sm.logger.logWithLevelFCtx(ctx, TRACE, sm, format, v...)
+	}
+}
+
+// Debug level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) DebugFCtx(ctx context.Context, format string, v ...any) {
+	if sm.logger.enabled(DEBUG, sm) {
+		sm.logger.logWithLevelFCtx(ctx, DEBUG, sm, format, v...)
+	}
+}
+
+// Info level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) InfoFCtx(ctx context.Context, format string, v ...any) {
+	if sm.logger.enabled(INFO, sm) {
+		sm.logger.logWithLevelFCtx(ctx, INFO, sm, format, v...)
+	}
+}
+
+// Notice level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) NoticeFCtx(ctx context.Context, format string, v ...any) {
+	if sm.logger.enabled(NOTICE, sm) {
+		sm.logger.logWithLevelFCtx(ctx, NOTICE, sm, format, v...)
+	}
+}
+
+// Warn level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) WarnFCtx(ctx context.Context, format string, v ...any) {
+	if sm.logger.enabled(WARN, sm) {
+		sm.logger.logWithLevelFCtx(ctx, WARN, sm, format, v...)
+	}
+}
+
+// Error level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) ErrorFCtx(ctx context.Context, format string, v ...any) {
+	if sm.logger.enabled(ERROR, sm) {
+		sm.logger.logWithLevelFCtx(ctx, ERROR, sm, format, v...)
+	}
+}
+
+// Fail level log with the trace ID and fields of ctx
+func (sm *SystemModuleLogger) FailFCtx(ctx context.Context, format string, v ...any) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevelFCtx(ctx, FAIL, sm, format, v...)
+	}
+}
+
+// Trace level log with the trace ID and fields of ctx
+func TraceCtx(ctx context.Context, msg ...string) {
+	if std.enabled(TRACE, nil) {
+		std.logWithLevelCtx(ctx, TRACE, nil, msg...)
+	}
+}
+
+// Debug level log with the trace ID and fields of ctx
+func DebugCtx(ctx context.Context, msg ...string) {
+	if std.enabled(DEBUG, nil) {
+		std.logWithLevelCtx(ctx, DEBUG, nil, msg...)
+	}
+}
+
+// Info level log with the trace ID and fields of ctx
+func InfoCtx(ctx context.Context, msg ...string) {
+	if std.enabled(INFO, nil) {
+		std.logWithLevelCtx(ctx, INFO, nil, msg...)
+	}
+}
+
+// Notice level log with the trace ID and fields of ctx
+func NoticeCtx(ctx context.Context, msg ...string) {
+	if std.enabled(NOTICE, nil) {
+		std.logWithLevelCtx(ctx, NOTICE, nil, msg...)
+	}
+}
+
+// Warn level log with the trace ID and fields of ctx
+func WarnCtx(ctx context.Context, msg ...string) {
+	if std.enabled(WARN, nil) {
+		std.logWithLevelCtx(ctx, WARN, nil, msg...)
+	}
+}
+
+// Error level log with the trace ID and fields of ctx
+func ErrorCtx(ctx context.Context, msg ...string) {
+	if std.enabled(ERROR, nil) {
+		std.logWithLevelCtx(ctx, ERROR, nil, msg...)
+	}
+}
+
+// Fail level log with the trace ID and fields of ctx
+func FailCtx(ctx context.Context, msg ...string) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevelCtx(ctx, FAIL, nil, msg...)
+	}
+}
+
+// Trace level log with the trace ID and fields of ctx
+func TraceFCtx(ctx context.Context, format string, v ...any) {
+	if std.enabled(TRACE, nil) {
+		std.logWithLevelFCtx(ctx, TRACE, nil, format, v...)
+	}
+}
+
+// Debug level log with the trace ID and fields of ctx
+func DebugFCtx(ctx context.Context, format string, v ...any) {
+	if std.enabled(DEBUG, nil) {
+		std.logWithLevelFCtx(ctx, DEBUG, nil, format, v...)
+	}
+}
+
+// Info level log with the trace ID and fields of ctx
+func InfoFCtx(ctx context.Context, format string, v ...any) {
+	if std.enabled(INFO, nil) {
+		std.logWithLevelFCtx(ctx, INFO, nil, format, v...)
+	}
+}
+
+// Notice level log with the trace ID and fields of ctx
+func NoticeFCtx(ctx context.Context, format string, v ...any) {
+	if std.enabled(NOTICE, nil) {
+		std.logWithLevelFCtx(ctx, NOTICE, nil, format, v...)
+	}
+}
+
+// Warn level log with the trace ID and fields of ctx
+func WarnFCtx(ctx context.Context, format string, v ...any) {
+	if std.enabled(WARN, nil) {
+		std.logWithLevelFCtx(ctx, WARN, nil, format, v...)
+	}
+}
+
+// Error level log with the trace ID and fields of ctx
+func ErrorFCtx(ctx context.Context, format string, v ...any) {
+	if std.enabled(ERROR, nil) {
+		std.logWithLevelFCtx(ctx, ERROR, nil, format, v...)
+	}
+}
+
+// Fail level log with the trace ID and fields of ctx
+func FailFCtx(ctx context.Context, format string, v ...any) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevelFCtx(ctx, FAIL, nil, format, v...)
+	}
+}
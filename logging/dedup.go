@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type dedupKey struct {
+	level   LogLevel
+	module  string
+	message string
+}
+
+type dedupState struct {
+	first    *Entry
+	repeated int
+	timer    *time.Timer
+}
+
+// dedupFilter collapses identical entries within a window.
+type dedupFilter struct {
+	window time.Duration
+	mu     sync.Mutex
+	seen   map[dedupKey]*dedupState
+}
+
+// EnableDedup collapses entries with the same level, module and message
+// logged within window of the first one. The first entry is written, the
+// repeats are counted and summarized as "last message repeated N times"
+// when the window ends.
+func (l *Logger) EnableDedup(window time.Duration) {
+	l.dedup.Store(&dedupFilter{window: window, seen: make(map[dedupKey]*dedupState)})
+}
+
+// allow reports whether the entry is the first of its kind in the window.
+func (d *dedupFilter) allow(l *Logger, e *Entry) bool {
+	key := dedupKey{level: e.Level, module: e.Module, message: e.Message}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if st, ok := d.seen[key]; ok {
+		st.repeated++
+		return false
+	}
+	d.seen[key] = &dedupState{
+		first: e,
+		timer: time.AfterFunc(d.window, func() { d.expire(l, key) }),
+	}
+	return true
+}
+
+// expire ends the window of key and writes the summary.
+func (d *dedupFilter) expire(l *Logger, key dedupKey) {
+	d.mu.Lock()
+	st, ok := d.seen[key]
+	delete(d.seen, key)
+	d.mu.Unlock()
+	if ok {
+		d.summarize(l, st)
+	}
+}
+
+// flush ends all open windows, so no repeat count is lost on shutdown.
+func (d *dedupFilter) flush(l *Logger) {
+	d.mu.Lock()
+	pending := d.seen
+	d.seen = make(map[dedupKey]*dedupState)
+	d.mu.Unlock()
+	for _, st := range pending {
+		st.timer.Stop()
+		d.summarize(l, st)
+	}
+}
+
+func (d *dedupFilter) summarize(l *Logger, st *dedupState) {
+	if st.repeated == 0 {
+		return
+	}
+	l.dispatch(&Entry{
+		Time:      time.Now(),
+		Level:     st.first.Level,
+		Module:    st.first.Module,
+		Message:   fmt.Sprintf("last message repeated %d times", st.repeated),
+		Fields:    []Field{Int("repeated", st.repeated)},
+		NameColor: st.first.NameColor,
+		TextColor: st.first.TextColor,
+	})
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Encoder turns an entry into a single line of output, without a trailing
+// newline.
+type Encoder interface {
+	Encode(e *Entry) ([]byte, error)
+}
+
+// TextEncoder renders the tab-separated "[LEVEL]\t[Module]\tmessage" layout,
+// colored unless DisableTextModifier is set. It is the default encoder of a
+// Logger.
+type TextEncoder struct {
+	DisableTextModifier bool
+	// TimeFormat prefixes every line with the entry time when set. Leave it
+	// empty when writing through a log.Logger that adds its own timestamp.
+	TimeFormat string
+	// Theme defaults to DefaultTheme
+	Theme *Theme
+	// ColorDepth down-samples 256-color and 24-bit styles
+	ColorDepth ColorDepth
+}
+
+func (enc TextEncoder) Encode(e *Entry) ([]byte, error) {
+	var b strings.Builder
+	theme := enc.Theme
+	if theme == nil {
+		theme = defaultTheme
+	}
+	style := theme.Levels[e.Level]
+	color, textColor := enc.style(style.Tag), enc.style(style.Text)
+	moduleColor := enc.style(theme.Module)
+	level := levelLabel(e.Level)
+
+	if enc.TimeFormat != "" && !e.Time.IsZero() {
+		if timeColor := enc.style(theme.Time); timeColor != "" && !enc.DisableTextModifier {
+			b.WriteString(string(timeColor))
+			b.WriteString(e.Time.Format(enc.TimeFormat))
+			b.WriteString(string(Reset))
+		} else {
+			b.WriteString(e.Time.Format(enc.TimeFormat))
+		}
+		b.WriteByte(' ')
+	}
+
+	if e.Module != "" {
+		nameColor := enc.style(e.NameColor)
+		if nameColor == "" && moduleColor != "" {
+			nameColor = Reset + moduleColor
+		}
+		if nameColor == "" {
+			textColor = Reset
+		}
+		if textColor == "" {
+			if e.TextColor == "" {
+				textColor = Reset
+			} else {
+				textColor = enc.style(e.TextColor)
+			}
+		}
+		if enc.DisableTextModifier {
+			fmt.Fprintf(&b, "[%s]\t[%s]\t", level, e.Module)
+		} else {
+			fmt.Fprintf(&b, "%s[%s]%s\t[%s]%s\t", color, level, nameColor, e.Module, textColor)
+		}
+	} else {
+		if textColor == "" {
+			textColor = Reset
+		}
+		if enc.DisableTextModifier {
+			fmt.Fprintf(&b, "[%s]\t[General]\t", level)
+		} else if moduleColor != "" {
+			fmt.Fprintf(&b, "%s[%s]%s\t[General]%s\t", color, level, Reset+moduleColor, Reset+textColor)
+		} else {
+			fmt.Fprintf(&b, "%s[%s]%s\t[General]\t", color, level, textColor)
+		}
+	}
+	if caller := e.ShortCaller(); caller != "" {
+		b.WriteString(caller)
+		b.WriteByte('\t')
+	}
+	appendFields(&b, e.Message, e.Fields, enc.style(theme.FieldKey), textColor, !enc.DisableTextModifier)
+	if !enc.DisableTextModifier {
+		b.WriteString(string(Reset))
+	}
+	if e.Stack != "" {
+		b.WriteByte('\n')
+		b.WriteString(e.Stack)
+	}
+	return []byte(b.String()), nil
+}
+
+// style adapts a modifier to the color depth of the encoder.
+func (enc TextEncoder) style(m TextModifier) TextModifier {
+	if m == "" || enc.DisableTextModifier {
+		return m
+	}
+	return m.Downsample(enc.ColorDepth)
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// Entry is a single log event as handed to an Encoder.
+type Entry struct {
+	Time    time.Time
+	Level   LogLevel
+	Module  string // empty for entries logged on the Logger itself
+	Message string
+	Fields  []Field
+
+	// Colors of the SystemModuleLogger, used by text encoders
+	NameColor TextModifier
+	TextColor TextModifier
+
+	// PC is the program counter of the call site. It is only set when a
+	// feature of the Logger needs it and 0 otherwise.
+	PC uintptr
+	// Caller is the call site, set when the Logger has EnableCaller.
+	Caller runtime.Frame
+	// Stack is the stack of the calling goroutine, set at the levels chosen
+	// with EnableStacktrace.
+	Stack string
+
+	// ctx is the context of *Ctx calls, passed on to a slog.Handler
+	ctx context.Context
+}
+
+// ModuleName returns the module of the entry, or "General" for entries
+// logged on the Logger itself.
+func (e *Entry) ModuleName() string {
+	if e.Module == "" {
+		return "General"
+	}
+	return e.Module
+}
+
+// levelLabel is the name a level is printed with. Printf entries are logged
+// at NONE and show up as "????".
+func levelLabel(level LogLevel) string {
+	if _, ok := levelNames[level]; !ok || level == NONE {
+		return "????"
+	}
+	return level.String()
+}
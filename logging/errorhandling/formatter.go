@@ -1,6 +1,7 @@
 package errorhandling
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -31,8 +32,18 @@ func (e *CustomError) HTML() (HtmlError, int) {
 		TraceId:     e.TraceId,
 	}, e.HttpCode
 }
+
+// fieldLogger is implemented by *logging.Logger and
+// *logging.SystemModuleLogger.
+type fieldLogger interface {
+	DebugW(msg string, fields ...logging.Field)
+	WarnW(msg string, fields ...logging.Field)
+	ErrorW(msg string, fields ...logging.Field)
+	FailW(msg string, fields ...logging.Field)
+}
+
 func (e *CustomError) Log() *CustomError {
-	var sml logging.LoggerInterface
+	var sml fieldLogger
 
 	if e.Source.SML != nil {
 		sml = e.Source.SML
@@ -42,21 +53,36 @@ func (e *CustomError) Log() *CustomError {
 			sml = std.logger
 		}
 	}
+	msg := fmt.Sprintf("{trc-%s}\t%s", e.TraceId, formatPrintable(e.LogMessage))
+	// The trace ID is also passed as field, so it matches the "trace_id" of
+	// entries logged with a context carrying the same ID.
+	traceId := logging.String(logging.TraceIDKey, e.TraceId)
 	switch e.Level {
 	case ErrorWARN:
-		sml.Warn(fmt.Sprintf("{trc-%s}\t%s", e.TraceId, formatPrintable(e.LogMessage)))
+		sml.WarnW(msg, traceId)
 	case ErrorWrongUsage:
-		sml.Debug(fmt.Sprintf("{trc-%s}\t%s", e.TraceId, formatPrintable(e.LogMessage)))
+		sml.DebugW(msg, traceId)
 	case ErrorMedium:
-		sml.Error(fmt.Sprintf("{trc-%s}\t%s", e.TraceId, formatPrintable(e.LogMessage)))
+		sml.ErrorW(msg, traceId)
 	case ErrorFail:
-		sml.Fail(fmt.Sprintf("{trc-%s}\t%s", e.TraceId, formatPrintable(e.LogMessage)))
+		sml.FailW(msg, traceId)
 	default:
-		sml.Error(fmt.Sprintf("{trc-%s}\t%s", e.TraceId, formatPrintable(e.LogMessage)))
+		sml.ErrorW(msg, traceId)
+	}
+	return e
+}
+
+// WithContext adopts the trace ID stored in ctx with
+// logging.ContextWithTraceID, so the error is reported under the same ID as
+// the other log entries of the request.
+func (e *CustomError) WithContext(ctx context.Context) *CustomError {
+	if traceId := logging.TraceIDFromContext(ctx); traceId != "" {
+		e.TraceId = traceId
 	}
 	return e
 }
 func (e *CustomError) HandelWeb(w http.ResponseWriter, r *http.Request) bool {
+	e.WithContext(r.Context())
 	if !e.ContinueExecution {
 		e.Log()
 
@@ -71,6 +97,7 @@ func (e *CustomError) HandelWeb(w http.ResponseWriter, r *http.Request) bool {
 	return true
 }
 func (e *CustomError) HandelWebExit(w http.ResponseWriter, r *http.Request) *CustomError {
+	e.WithContext(r.Context())
 	e.Log()
 
 	html, HttpCode := e.HTML()
@@ -1,6 +1,7 @@
 package errorhandling
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 
@@ -73,6 +74,12 @@ func NewCustomError(preset CustomErrorPreset) *CustomError {
 	return preset.New()
 }
 
+// NewCtx creates the error with the trace ID stored in ctx, or a new one if
+// ctx has none.
+func (preset *CustomErrorPreset) NewCtx(ctx context.Context) *CustomError {
+	return preset.New().WithContext(ctx)
+}
+
 func (preset *CustomErrorPreset) New() *CustomError {
 	if preset.PresetID == 0 {
 		presetIDcounter++
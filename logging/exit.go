@@ -0,0 +1,224 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+var shutdownHooks struct {
+	mu    sync.Mutex
+	hooks []func()
+}
+
+// RegisterShutdownHook adds a function that Fatal and Panic run after
+// flushing the outputs, e.g. to close connections. Hooks run in the order
+// they were registered; a panicking hook does not stop the others.
+func RegisterShutdownHook(hook func()) {
+	shutdownHooks.mu.Lock()
+	defer shutdownHooks.mu.Unlock()
+	shutdownHooks.hooks = append(shutdownHooks.hooks, hook)
+}
+
+func runShutdownHooks() {
+	shutdownHooks.mu.Lock()
+	hooks := append([]func(){}, shutdownHooks.hooks...)
+	shutdownHooks.mu.Unlock()
+	for _, hook := range hooks {
+		func() {
+			defer func() { _ = recover() }()
+			hook()
+		}()
+	}
+}
+
+// SetExitFunc replaces os.Exit for Fatal, e.g. in tests. nil restores
+// os.Exit.
+func (l *Logger) SetExitFunc(exit func(code int)) {
+	l.exit = exit
+}
+
+// syncer is implemented by sinks that buffer output, like FileSink.
+type syncer interface {
+	Sync() error
+}
+
+// shutdown flushes the queue and the sinks and runs the shutdown hooks.
+func (l *Logger) shutdown() {
+	l.Flush()
+	for _, rs := range l.sinks.list() {
+		if s, ok := rs.sink.(syncer); ok {
+			_ = s.Sync()
+		}
+	}
+	runShutdownHooks()
+}
+
+func (l *Logger) exitNow() {
+	l.shutdown()
+	if l.exit != nil {
+		l.exit(1)
+		return
+	}
+	os.Exit(1)
+}
+
+// Fatal logs at FAIL level, flushes all outputs, runs the shutdown hooks and
+// exits with status 1
+func (l *Logger) Fatal(msg ...string) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevel(FAIL, nil, msg...)
+	}
+	l.exitNow()
+}
+
+// FatalF logs at FAIL level and exits like Fatal
+func (l *Logger) FatalF(format string, v ...any) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevelF(FAIL, nil, format, v...)
+	}
+	l.exitNow()
+}
+
+// FatalW logs at FAIL level with fields and exits like Fatal
+func (l *Logger) FatalW(msg string, fields ...Field) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevelW(FAIL, nil, msg, fields...)
+	}
+	l.exitNow()
+}
+
+// Panic logs at FAIL level, flushes all outputs, runs the shutdown hooks and
+// panics with the message
+func (l *Logger) Panic(msg ...string) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevel(FAIL, nil, msg...)
+	}
+	l.shutdown()
+	panic(strings.Join(msg, " "))
+}
+
+// PanicF logs at FAIL level and panics like Panic
+func (l *Logger) PanicF(format string, v ...any) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevelF(FAIL, nil, format, v...)
+	}
+	l.shutdown()
+	panic(fmt.Sprintf(format, v...))
+}
+
+// PanicW logs at FAIL level with fields and panics like Panic
+func (l *Logger) PanicW(msg string, fields ...Field) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevelW(FAIL, nil, msg, fields...)
+	}
+	l.shutdown()
+	panic(msg)
+}
+
+// Fatal logs at FAIL level, flushes all outputs, runs the shutdown hooks and
+// exits with status 1
+func (sm *SystemModuleLogger) Fatal(msg ...string) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevel(FAIL, sm, msg...)
+	}
+	sm.logger.exitNow()
+}
+
+// FatalF logs at FAIL level and exits like Fatal
+func (sm *SystemModuleLogger) FatalF(format string, v ...any) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevelF(FAIL, sm, format, v...)
+	}
+	sm.logger.exitNow()
+}
+
+// FatalW logs at FAIL level with fields and exits like Fatal
+func (sm *SystemModuleLogger) FatalW(msg string, fields ...Field) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevelW(FAIL, sm, msg, fields...)
+	}
+	sm.logger.exitNow()
+}
+
+// Panic logs at FAIL level, flushes all outputs, runs the shutdown hooks and
+// panics with the message
+func (sm *SystemModuleLogger) Panic(msg ...string) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevel(FAIL, sm, msg...)
+	}
+	sm.logger.shutdown()
+	panic(strings.Join(msg, " "))
+}
+
+// PanicF logs at FAIL level and panics like Panic
+func (sm *SystemModuleLogger) PanicF(format string, v ...any) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevelF(FAIL, sm, format, v...)
+	}
+	sm.logger.shutdown()
+	panic(fmt.Sprintf(format, v...))
+}
+
+// PanicW logs at FAIL level with fields and panics like Panic
+func (sm *SystemModuleLogger) PanicW(msg string, fields ...Field) {
+	if sm.logger.enabled(FAIL, sm) {
+		sm.logger.logWithLevelW(FAIL, sm, msg, fields...)
+	}
+	sm.logger.shutdown()
+	panic(msg)
+}
+
+// Fatal logs at FAIL level, flushes all outputs, runs the shutdown hooks and
+// exits with status 1
+func Fatal(msg ...string) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevel(FAIL, nil, msg...)
+	}
+	std.exitNow()
+}
+
+// FatalF logs at FAIL level and exits like Fatal
+func FatalF(format string, v ...any) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevelF(FAIL, nil, format, v...)
+	}
+	std.exitNow()
+}
+
+// FatalW logs at FAIL level with fields and exits like Fatal
+func FatalW(msg string, fields ...Field) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevelW(FAIL, nil, msg, fields...)
+	}
+	std.exitNow()
+}
+
+// Panic logs at FAIL level, flushes all outputs, runs the shutdown hooks and
+// panics with the message
+func Panic(msg ...string) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevel(FAIL, nil, msg...)
+	}
+	std.shutdown()
+	panic(strings.Join(msg, " "))
+}
+
+// PanicF logs at FAIL level and panics like Panic
+func PanicF(format string, v ...any) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevelF(FAIL, nil, format, v...)
+	}
+	std.shutdown()
+	panic(fmt.Sprintf(format, v...))
+}
+
+// PanicW logs at FAIL level with fields and panics like Panic
+func PanicW(msg string, fields ...Field) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevelW(FAIL, nil, msg, fields...)
+	}
+	std.shutdown()
+	panic(msg)
+}
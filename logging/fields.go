@@ -0,0 +1,165 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Field is a typed key/value pair attached to a log entry.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String field
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int field
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 field
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration field
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool field
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err adds err under the key "error"
+func Err(err error) Field {
+	return NamedErr("error", err)
+}
+
+// NamedErr adds err under the given key
+func NamedErr(key string, err error) Field {
+	return Field{Key: key, Value: err}
+}
+
+// Any field, rendered with fmt for text output
+func Any(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Group nests fields under key. JSON output renders a nested object, text
+// output prefixes the keys with "key.".
+func Group(key string, fields ...Field) Field {
+	return Field{Key: key, Value: fields}
+}
+
+// String renders the value the way the text output shows it.
+func (f Field) String() string {
+	switch v := f.Value.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// fieldsColumn is the width messages are padded to so fields line up.
+const fieldsColumn = 40
+
+// appendFields renders fields as key=value pairs after message, padding the
+// message so consecutive entries show their fields in the same column.
+func appendFields(b *strings.Builder, message string, fields []Field, keyColor TextModifier, textColor TextModifier, colored bool) {
+	b.WriteString(message)
+	if len(fields) == 0 {
+		return
+	}
+	if pad := fieldsColumn - len(message); pad > 0 {
+		b.WriteString(strings.Repeat(" ", pad))
+	}
+	for _, f := range flattenFields("", fields) {
+		b.WriteByte(' ')
+		if colored {
+			b.WriteString(string(keyColor))
+			b.WriteString(f.Key)
+			b.WriteString(string(Reset + Dim))
+			b.WriteByte('=')
+			b.WriteString(string(Reset + textColor))
+		} else {
+			b.WriteString(f.Key)
+			b.WriteByte('=')
+		}
+		b.WriteString(quoteIfNeeded(f.String()))
+	}
+}
+
+// quoteIfNeeded quotes values that would otherwise be ambiguous in key=value
+// output: empty values and values with spaces, quotes, '=' or control
+// characters.
+func quoteIfNeeded(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '"' || r == '=' || r == 0x7f || r == utf8.RuneError {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// FlattenFields replaces groups by their members with dotted keys, the way
+// the text and logfmt output show them.
+func FlattenFields(fields []Field) []Field {
+	return flattenFields("", fields)
+}
+
+// flattenFields replaces groups by their members with dotted keys.
+func flattenFields(prefix string, fields []Field) []Field {
+	flat := fields
+	for i, f := range fields {
+		if _, ok := f.Value.([]Field); ok || prefix != "" {
+			flat = make([]Field, 0, len(fields))
+			flat = append(flat, fields[:i]...)
+			for _, f := range fields[i:] {
+				if group, ok := f.Value.([]Field); ok {
+					flat = append(flat, flattenFields(prefix+f.Key+".", group)...)
+				} else {
+					flat = append(flat, Field{Key: prefix + f.Key, Value: f.Value})
+				}
+			}
+			break
+		}
+	}
+	return flat
+}
+
+// joinFields returns a new slice so children never share a backing array.
+func joinFields(groups ...[]Field) []Field {
+	n := 0
+	for _, g := range groups {
+		n += len(g)
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make([]Field, 0, n)
+	for _, g := range groups {
+		out = append(out, g...)
+	}
+	return out
+}
@@ -0,0 +1,369 @@
+package logging
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateInterval selects time based rotation of a FileSink.
+type RotateInterval int
+
+const (
+	RotateNever RotateInterval = iota
+	RotateHourly
+	RotateDaily
+)
+
+// backupTimeFormat is part of the backup file names. It sorts in time order.
+const backupTimeFormat = "2006-01-02T15-04-05.000000000"
+
+// FileSinkOptions configures a FileSink.
+type FileSinkOptions struct {
+	// Filename is the file entries are written to. Backups are created next
+	// to it as name-<timestamp>.ext.
+	Filename string
+	// MaxSize rotates the file before it grows beyond this many bytes.
+	// 0 disables size based rotation.
+	MaxSize int64
+	// Interval rotates the file when the hour or day of the entries changes.
+	Interval RotateInterval
+	// MaxBackups keeps at most this many backups. 0 keeps all.
+	MaxBackups int
+	// MaxAge removes backups older than this. 0 keeps all.
+	MaxAge time.Duration
+	// Compress gzips backups in the background.
+	Compress bool
+	// Encoder defaults to a TextEncoder without colors.
+	Encoder Encoder
+	// FileMode of newly created files, defaults to 0644.
+	FileMode os.FileMode
+}
+
+// FileSink writes entries to a file and rotates it by size and time.
+type FileSink struct {
+	options FileSinkOptions
+
+	mu     sync.Mutex
+	file   *os.File // nil after a failed rotation, reopened by Write
+	size   int64
+	period time.Time
+	closed bool
+
+	// millMu serializes compression and cleanup of backups, wg tracks them
+	millMu sync.Mutex
+	wg     sync.WaitGroup
+}
+
+// NewFileSink opens or creates the file and returns a sink writing to it.
+func NewFileSink(options FileSinkOptions) (*FileSink, error) {
+	if options.Filename == "" {
+		return nil, errors.New("logging: FileSink needs a Filename")
+	}
+	if options.Encoder == nil {
+		options.Encoder = TextEncoder{DisableTextModifier: true, TimeFormat: time.RFC3339}
+	}
+	if options.FileMode == 0 {
+		options.FileMode = 0644
+	}
+	s := &FileSink{options: options}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open opens the file for appending. Callers hold s.mu.
+func (s *FileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.options.Filename), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(s.options.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, s.options.FileMode)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	s.size = info.Size()
+	if s.size > 0 {
+		s.period = s.periodOf(info.ModTime())
+	} else {
+		s.period = s.periodOf(time.Now())
+	}
+	return nil
+}
+
+// periodOf returns the start of the rotation period t falls in.
+func (s *FileSink) periodOf(t time.Time) time.Time {
+	switch s.options.Interval {
+	case RotateHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case RotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+func (s *FileSink) Write(e *Entry) error {
+	line, err := s.options.Encoder.Encode(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	t := e.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	sizeExceeded := s.options.MaxSize > 0 && s.size+int64(len(line)) > s.options.MaxSize
+	periodChanged := s.options.Interval != RotateNever && s.periodOf(t).After(s.period)
+	// An empty file is never rotated, it simply continues in the new period
+	if (sizeExceeded || periodChanged) && s.size > 0 {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	if periodChanged {
+		s.period = s.periodOf(t)
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+// Rotate moves the current file to a backup and starts a new one.
+func (s *FileSink) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.file == nil {
+		return s.open()
+	}
+	return s.rotate()
+}
+
+// rotate renames the file to a backup, reopens it and starts compression and
+// cleanup in the background. Callers hold s.mu.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+	backup := s.backupName(time.Now())
+	if err := os.Rename(s.options.Filename, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		// Keep writing to the current file; if it cannot be opened either,
+		// the next Write tries again
+		_ = s.open()
+		return err
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.mill(backup)
+	}()
+	return nil
+}
+
+// Reopen closes and reopens the file, for use after an external tool moved
+// it away.
+func (s *FileSink) Reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			return err
+		}
+		s.file = nil
+	}
+	return s.open()
+}
+
+// ReopenOn reopens the file whenever one of sigs (e.g. syscall.SIGHUP) is
+// received, until stop is called.
+func (s *FileSink) ReopenOn(sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				_ = s.Reopen()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Sync commits the written lines to storage.
+func (s *FileSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return os.ErrClosed
+	}
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file and waits for pending compression and cleanup.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	var err error
+	if s.file != nil {
+		err = s.file.Close()
+		s.file = nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// splitFilename returns the parts backups are named from: dir/prefix and ext.
+func (s *FileSink) splitFilename() (string, string) {
+	ext := filepath.Ext(s.options.Filename)
+	return strings.TrimSuffix(s.options.Filename, ext) + "-", ext
+}
+
+func (s *FileSink) backupName(t time.Time) string {
+	prefix, ext := s.splitFilename()
+	for {
+		name := prefix + t.Format(backupTimeFormat) + ext
+		_, err := os.Lstat(name)
+		_, errGz := os.Lstat(name + ".gz")
+		// Any error means there is no file to collide with, e.g. when the
+		// directory is gone; rename then reports the actual problem
+		if err != nil && errGz != nil {
+			return name
+		}
+		t = t.Add(time.Nanosecond)
+	}
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+// backups lists the backups of the file, newest first.
+func (s *FileSink) backups() ([]backupFile, error) {
+	prefix, ext := s.splitFilename()
+	entries, err := os.ReadDir(filepath.Dir(s.options.Filename))
+	if err != nil {
+		return nil, err
+	}
+	base := filepath.Base(prefix)
+	var backups []backupFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, base) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, base)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		t, err := time.ParseInLocation(backupTimeFormat, strings.TrimSuffix(stamp, ext), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(filepath.Dir(prefix), name), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+	return backups, nil
+}
+
+// mill compresses a fresh backup and removes backups beyond the retention
+// limits.
+func (s *FileSink) mill(backup string) {
+	s.millMu.Lock()
+	defer s.millMu.Unlock()
+
+	if s.options.Compress {
+		_ = compressFile(backup, s.options.FileMode)
+	}
+	if s.options.MaxBackups <= 0 && s.options.MaxAge <= 0 {
+		return
+	}
+	backups, err := s.backups()
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-s.options.MaxAge)
+	for i, b := range backups {
+		if (s.options.MaxBackups > 0 && i >= s.options.MaxBackups) ||
+			(s.options.MaxAge > 0 && b.time.Before(cutoff)) {
+			_ = os.Remove(b.path)
+		}
+	}
+}
+
+// compressFile gzips name to name.gz and removes name.
+func compressFile(name string, mode os.FileMode) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		dst.Close()
+		os.Remove(name + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		dst.Close()
+		os.Remove(name + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
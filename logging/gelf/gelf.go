@@ -0,0 +1,267 @@
+// Package gelf provides a logging.Sink that sends entries to Graylog as
+// GELF 1.1 messages over UDP or TCP.
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/internal/netconn"
+)
+
+// Compression of UDP messages. TCP messages are never compressed.
+type Compression int
+
+const (
+	CompressGzip Compression = iota
+	CompressZlib
+	CompressNone
+)
+
+const (
+	// DefaultChunkSize fits a chunk into the MTU of most WAN links
+	DefaultChunkSize = 1420
+	// maxChunks is the limit of the GELF chunking protocol
+	maxChunks   = 128
+	chunkHeader = 12
+)
+
+// Level maps a log level to the syslog severity GELF uses.
+func Level(level logging.LogLevel) int {
+	switch level {
+	case logging.TRACE, logging.DEBUG:
+		return 7
+	case logging.NOTICE:
+		return 5
+	case logging.WARN:
+		return 4
+	case logging.ERROR:
+		return 3
+	case logging.FAIL:
+		return 2
+	default:
+		return 6
+	}
+}
+
+// Options configures a Sink.
+type Options struct {
+	// Network is "udp" or "tcp", defaults to "udp"
+	Network string
+	// Address of the Graylog input
+	Address string
+	// Host defaults to os.Hostname
+	Host        string
+	Compression Compression
+	// ChunkSize is the largest UDP datagram, defaults to DefaultChunkSize
+	ChunkSize int
+	// Timeout for connecting and writing, defaults to 5 seconds
+	Timeout time.Duration
+}
+
+// Sink writes GELF messages. The module is sent as _module and fields as
+// additional fields; messages spanning several lines or carrying a stack
+// also get a full_message. It reconnects when the connection is lost.
+type Sink struct {
+	options Options
+
+	mu   sync.Mutex
+	conn netconn.Conn
+}
+
+// New returns a sink and opens the connection.
+func New(options Options) (*Sink, error) {
+	if options.Network == "" {
+		options.Network = "udp"
+	}
+	if options.Host == "" {
+		options.Host, _ = os.Hostname()
+	}
+	if options.ChunkSize <= chunkHeader {
+		options.ChunkSize = DefaultChunkSize
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	s := &Sink{options: options}
+	s.conn.Dial = s.dial
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.conn.Connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// dial connects to the configured address.
+func (s *Sink) dial() (net.Conn, error) {
+	return net.DialTimeout(s.options.Network, s.options.Address, s.options.Timeout)
+}
+
+// Write encodes the entry and sends it. A failed write is retried once on a
+// new connection.
+func (s *Sink) Write(e *logging.Entry) error {
+	msg, err := s.encode(e)
+	if err != nil {
+		return err
+	}
+	if !s.stream() {
+		if msg, err = s.compress(msg); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.Send(func(conn net.Conn) error { return s.send(conn, msg) })
+}
+
+// Close closes the connection.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.Close()
+}
+
+func (s *Sink) stream() bool {
+	return strings.HasPrefix(s.options.Network, "tcp")
+}
+
+// send writes a null-byte terminated message on streams and chunks large
+// datagrams.
+func (s *Sink) send(conn net.Conn, msg []byte) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(s.options.Timeout))
+	if s.stream() {
+		_, err := conn.Write(append(msg, 0))
+		return err
+	}
+	if len(msg) <= s.options.ChunkSize {
+		_, err := conn.Write(msg)
+		return err
+	}
+
+	size := s.options.ChunkSize - chunkHeader
+	count := (len(msg) + size - 1) / size
+	if count > maxChunks {
+		return errors.New("gelf: message too large for chunking")
+	}
+	chunk := make([]byte, 0, s.options.ChunkSize)
+	chunk = append(chunk, 0x1e, 0x0f)
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	chunk = append(chunk, id...)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*size, len(msg))
+		chunk = append(chunk[:10], byte(i), byte(count))
+		chunk = append(chunk, msg[i*size:end]...)
+		if _, err := conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Sink) compress(msg []byte) ([]byte, error) {
+	var b bytes.Buffer
+	var w io.WriteCloser
+	switch s.options.Compression {
+	case CompressGzip:
+		w = gzip.NewWriter(&b)
+	case CompressZlib:
+		w = zlib.NewWriter(&b)
+	default:
+		return msg, nil
+	}
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (s *Sink) encode(e *logging.Entry) ([]byte, error) {
+	t := e.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	msg := map[string]any{
+		"version":   "1.1",
+		"host":      s.options.Host,
+		"timestamp": float64(t.UnixMicro()) / 1e6,
+		"level":     Level(e.Level),
+		"_module":   e.ModuleName(),
+	}
+	short, _, multiline := strings.Cut(e.Message, "\n")
+	if short == "" {
+		// short_message is required and may not be empty
+		short = "-"
+	}
+	msg["short_message"] = short
+	if multiline || e.Stack != "" {
+		full := e.Message
+		if e.Stack != "" {
+			full += "\n" + e.Stack
+		}
+		msg["full_message"] = full
+	}
+	if e.Caller.File != "" {
+		msg["_file"] = e.Caller.File
+		msg["_line"] = e.Caller.Line
+		msg["_function"] = e.Caller.Function
+	}
+	for _, f := range logging.FlattenFields(e.Fields) {
+		msg[FieldName(f.Key)] = value(f)
+	}
+	return json.Marshal(msg)
+}
+
+// FieldName returns the additional field name of a field key: prefixed with
+// '_', with characters other than letters, digits, '_', '.' and '-'
+// replaced. The reserved "_id" and the names the sink sets itself, "_module",
+// "_file", "_line" and "_function", get a second '_'.
+func FieldName(key string) string {
+	name := []byte("_" + key)
+	for i, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-') {
+			name[i] = '_'
+		}
+	}
+	switch string(name) {
+	case "_id", "_module", "_file", "_line", "_function":
+		return "_" + string(name)
+	}
+	return string(name)
+}
+
+// value keeps numbers native, GELF allows only strings and numbers.
+func value(f logging.Field) any {
+	switch v := f.Value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return v
+	case float32:
+		if !math.IsInf(float64(v), 0) && !math.IsNaN(float64(v)) {
+			return v
+		}
+	case float64:
+		if !math.IsInf(v, 0) && !math.IsNaN(v) {
+			return v
+		}
+	}
+	return f.String()
+}
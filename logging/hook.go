@@ -0,0 +1,91 @@
+package logging
+
+import "sync"
+
+// Hook is a step of the entry pipeline. It runs after the level check and
+// redaction and before deduplication, rate limiting and output. A hook may
+// modify e and passes it on by calling next; not calling next drops the
+// entry, calling it again with a Clone duplicates it.
+type Hook func(e *Entry, next func(e *Entry))
+
+// hookSet is a copy on write list of hooks.
+type hookSet struct {
+	mu    sync.RWMutex
+	hooks []Hook
+}
+
+func (set *hookSet) add(hook Hook) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	hooks := make([]Hook, 0, len(set.hooks)+1)
+	hooks = append(hooks, set.hooks...)
+	set.hooks = append(hooks, hook)
+}
+
+func (set *hookSet) list() []Hook {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.hooks
+}
+
+// AddHook appends a hook that sees every entry of the Logger, its children
+// and its modules. With redaction enabled hooks only see redacted entries,
+// and fields they add are not redacted; a hook that needs the raw values
+// must run on a Logger without redaction.
+func (l *Logger) AddHook(hook Hook) {
+	l.hooks.add(hook)
+}
+
+// AddHook appends a hook that only sees the entries of this module and its
+// child modules. It runs after the hooks of the Logger and parent modules.
+func (sm *SystemModuleLogger) AddHook(hook Hook) {
+	sm.hooks.add(hook)
+}
+
+// Clone returns a copy of the entry that can be modified independently.
+func (e *Entry) Clone() *Entry {
+	clone := *e
+	clone.Fields = joinFields(e.Fields)
+	return &clone
+}
+
+// process redacts the entry, runs the hooks of the Logger and of module and
+// its parents and writes it.
+func (l *Logger) process(e *Entry, module *SystemModuleLogger) {
+	if r := l.redactor.Load(); r != nil {
+		r.redact(e)
+	}
+	hooks := l.hooks.list()
+	if module != nil {
+		// Collect the module hooks innermost first, then run them outermost
+		// first after the hooks of the Logger
+		var moduleHooks [][]Hook
+		for m := module; m != nil; m = m.parent {
+			if list := m.hooks.list(); len(list) > 0 {
+				moduleHooks = append(moduleHooks, list)
+			}
+		}
+		if len(moduleHooks) > 0 {
+			hooks = append([]Hook(nil), hooks...)
+			for i := len(moduleHooks) - 1; i >= 0; i-- {
+				hooks = append(hooks, moduleHooks[i]...)
+			}
+		}
+	}
+	if len(hooks) == 0 {
+		l.write(e)
+		return
+	}
+	l.runHooks(hooks, e)
+}
+
+func (l *Logger) runHooks(hooks []Hook, e *Entry) {
+	if e == nil {
+		return
+	}
+	if len(hooks) == 0 {
+		l.write(e)
+		return
+	}
+	hooks[0](e, func(e *Entry) { l.runHooks(hooks[1:], e) })
+}
@@ -0,0 +1,238 @@
+package httpsink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+// Loki encodes batches for the Loki push API (/loki/api/v1/push). Entries are
+// grouped into streams labeled with level and module.
+type Loki struct {
+	// Labels are added to every stream, e.g. {"app": "shop"}
+	Labels map[string]string
+	// Encoder renders the log line, defaults to logging.LogfmtEncoder
+	Encoder logging.Encoder
+}
+
+func (Loki) ContentType() string { return "application/json" }
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (f Loki) Encode(entries []*logging.Entry) ([]byte, error) {
+	encoder := f.Encoder
+	if encoder == nil {
+		encoder = logging.LogfmtEncoder{}
+	}
+	streams := map[string]*lokiStream{}
+	for _, e := range entries {
+		level, module := levelName(e.Level), e.ModuleName()
+		key := level + "\x00" + module
+		stream, ok := streams[key]
+		if !ok {
+			labels := make(map[string]string, len(f.Labels)+2)
+			for k, v := range f.Labels {
+				labels[k] = v
+			}
+			labels["level"], labels["module"] = level, module
+			stream = &lokiStream{Stream: labels}
+			streams[key] = stream
+		}
+		line, err := encoder.Encode(e)
+		if err != nil {
+			return nil, err
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(timestamp(e).UnixNano(), 10), string(line)})
+	}
+
+	keys := make([]string, 0, len(streams))
+	for key := range streams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	body := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{Streams: make([]*lokiStream, 0, len(keys))}
+	for _, key := range keys {
+		body.Streams = append(body.Streams, streams[key])
+	}
+	return json.Marshal(body)
+}
+
+// Elasticsearch encodes batches for the _bulk API of Elasticsearch and
+// OpenSearch. Every entry becomes a document with @timestamp, level, module,
+// message and its fields under their dotted keys.
+type Elasticsearch struct {
+	// Index or data stream, defaults to "logs"
+	Index string
+}
+
+func (Elasticsearch) ContentType() string { return "application/x-ndjson" }
+
+func (f Elasticsearch) Encode(entries []*logging.Entry) ([]byte, error) {
+	index := f.Index
+	if index == "" {
+		index = "logs"
+	}
+	// create works for data streams and regular indices
+	action, err := json.Marshal(map[string]any{"create": map[string]string{"_index": index}})
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	for _, e := range entries {
+		doc := document(e)
+		doc["@timestamp"] = timestamp(e).Format(time.RFC3339Nano)
+		line, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(action)
+		b.WriteByte('\n')
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// CheckResponse reports documents the bulk API rejected.
+func (Elasticsearch) CheckResponse(body []byte) error {
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || !resp.Errors {
+		return nil
+	}
+	failed := 0
+	var first string
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Status >= 300 {
+				if failed == 0 {
+					first = result.Error.Type + ": " + result.Error.Reason
+				}
+				failed++
+			}
+		}
+	}
+	return &rejectedError{rejected: failed, total: len(resp.Items), first: first}
+}
+
+// rejectedError reports documents of a batch the backend rejected while it
+// accepted the others. Only the rejected ones count as dropped.
+type rejectedError struct {
+	rejected, total int
+	first           string
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("bulk request rejected %d of %d documents, first: %s", e.rejected, e.total, e.first)
+}
+
+// Splunk encodes batches for the HTTP Event Collector
+// (/services/collector/event). Set the "Authorization: Splunk <token>"
+// header in Options.Header.
+type Splunk struct {
+	// Host, Source, SourceType and Index are optional event metadata
+	Host       string
+	Source     string
+	SourceType string
+	Index      string
+}
+
+func (Splunk) ContentType() string { return "application/json" }
+
+func (f Splunk) Encode(entries []*logging.Entry) ([]byte, error) {
+	var b bytes.Buffer
+	for _, e := range entries {
+		event := map[string]any{
+			"time":  float64(timestamp(e).UnixMicro()) / 1e6,
+			"event": document(e),
+		}
+		for key, value := range map[string]string{"host": f.Host, "source": f.Source, "sourcetype": f.SourceType, "index": f.Index} {
+			if value != "" {
+				event[key] = value
+			}
+		}
+		line, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// reserved are the document keys set by the sink.
+var reserved = map[string]struct{}{"@timestamp": {}, "level": {}, "module": {}, "message": {}, "caller": {}, "stack": {}}
+
+// document returns level, module, message, caller, stack and the fields of
+// an entry. Fields named like one of those keys are moved under "fields.",
+// e.g. "fields.message".
+func document(e *logging.Entry) map[string]any {
+	doc := map[string]any{
+		"level":   levelName(e.Level),
+		"module":  e.ModuleName(),
+		"message": e.Message,
+	}
+	if caller := e.ShortCaller(); caller != "" {
+		doc["caller"] = caller
+	}
+	if e.Stack != "" {
+		doc["stack"] = e.Stack
+	}
+	for _, field := range logging.FlattenFields(e.Fields) {
+		key := field.Key
+		if _, ok := reserved[key]; ok {
+			key = "fields." + key
+		}
+		doc[key] = value(field)
+	}
+	return doc
+}
+
+// value keeps numbers and booleans native.
+func value(f logging.Field) any {
+	switch v := f.Value.(type) {
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return v
+	case float32:
+		if !math.IsInf(float64(v), 0) && !math.IsNaN(float64(v)) {
+			return v
+		}
+	case float64:
+		if !math.IsInf(v, 0) && !math.IsNaN(v) {
+			return v
+		}
+	}
+	return f.String()
+}
+
+func levelName(level logging.LogLevel) string {
+	return strings.ToLower(level.String())
+}
+
+func timestamp(e *logging.Entry) time.Time {
+	if e.Time.IsZero() {
+		return time.Now()
+	}
+	return e.Time
+}
@@ -0,0 +1,392 @@
+// Package httpsink provides a logging.Sink that ships entries in batches to
+// HTTP log backends such as Loki, Elasticsearch/OpenSearch and Splunk HEC.
+package httpsink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+// ErrClosed is returned by Write after Close.
+var ErrClosed = errors.New("httpsink: sink closed")
+
+// Format builds request bodies from batches of entries.
+type Format interface {
+	ContentType() string
+	Encode(entries []*logging.Entry) ([]byte, error)
+}
+
+// responseChecker is implemented by formats whose backend reports failures
+// of single entries in a successful response, like the bulk API.
+type responseChecker interface {
+	CheckResponse(body []byte) error
+}
+
+// Options configures a Sink.
+type Options struct {
+	// URL of the push endpoint, e.g. http://loki:3100/loki/api/v1/push
+	URL    string
+	Format Format
+	// Header is added to every request, e.g. for authentication
+	Header http.Header
+	// Client defaults to a client with a 10 second timeout
+	Client *http.Client
+	// Gzip compresses request bodies
+	Gzip bool
+	// BatchSize is the number of entries sent at most per request,
+	// defaults to 500
+	BatchSize int
+	// BatchBytes sends a batch once its messages and fields reach about this
+	// size, defaults to 1 MiB
+	BatchBytes int
+	// FlushInterval is the longest an entry waits for its batch to fill,
+	// defaults to 1 second
+	FlushInterval time.Duration
+	// QueueSize is the number of batches waiting to be sent; further batches
+	// are dropped. Defaults to 8.
+	QueueSize int
+	// MaxRetries of a failed request, defaults to 5. Negative disables
+	// retries.
+	MaxRetries int
+	// MinBackoff and MaxBackoff bound the exponential backoff between
+	// retries, default 100ms and 30s. MaxBackoff also caps Retry-After.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// OnError is called with errors of batches that could not be delivered,
+	// defaults to printing to stderr
+	OnError func(err error)
+}
+
+// Sink collects entries into batches and sends them from a background
+// goroutine. Network errors, 429 and 5xx responses are retried with
+// exponential backoff, honoring Retry-After up to MaxBackoff.
+type Sink struct {
+	options Options
+
+	mu     sync.Mutex
+	batch  []*logging.Entry
+	size   int
+	timer  *time.Timer
+	closed bool
+
+	queue   chan []*logging.Entry
+	pending int        // batches queued or being sent, guarded by mu
+	idle    *sync.Cond // signaled when pending drops to zero
+	dropped atomic.Uint64
+
+	done      chan struct{}
+	abort     chan struct{}
+	abortOnce sync.Once
+}
+
+// New returns a sink and starts its sender goroutine.
+func New(options Options) (*Sink, error) {
+	if options.URL == "" {
+		return nil, errors.New("httpsink: missing URL")
+	}
+	if options.Format == nil {
+		return nil, errors.New("httpsink: missing format")
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 500
+	}
+	if options.BatchBytes <= 0 {
+		options.BatchBytes = 1 << 20
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = 8
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = 5
+	}
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = 100 * time.Millisecond
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = 30 * time.Second
+	}
+	if options.OnError == nil {
+		options.OnError = func(err error) { fmt.Fprintln(os.Stderr, err) }
+	}
+	s := &Sink{
+		options: options,
+		queue:   make(chan []*logging.Entry, options.QueueSize),
+		done:    make(chan struct{}),
+		abort:   make(chan struct{}),
+	}
+	s.idle = sync.NewCond(&s.mu)
+	go s.run()
+	return s, nil
+}
+
+// Write adds the entry to the current batch.
+func (s *Sink) Write(e *logging.Entry) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrClosed
+	}
+	s.batch = append(s.batch, e.Clone())
+	s.size += entrySize(e)
+	if len(s.batch) == 1 {
+		s.timer = time.AfterFunc(s.options.FlushInterval, s.flushTimer)
+	}
+	var err error
+	if len(s.batch) >= s.options.BatchSize || s.size >= s.options.BatchBytes {
+		err = s.cut()
+	}
+	s.mu.Unlock()
+	s.report(err)
+	return nil
+}
+
+// entrySize estimates the encoded size of an entry.
+func entrySize(e *logging.Entry) int {
+	n := 64 + len(e.Message) + len(e.Module) + len(e.Stack)
+	for _, f := range logging.FlattenFields(e.Fields) {
+		n += len(f.Key) + len(f.String()) + 8
+	}
+	return n
+}
+
+func (s *Sink) flushTimer() {
+	var err error
+	s.mu.Lock()
+	if !s.closed && len(s.batch) > 0 {
+		err = s.cut()
+	}
+	s.mu.Unlock()
+	s.report(err)
+}
+
+// cut queues the current batch, or drops it if the queue is full. Callers
+// hold s.mu and pass the error to report once they released it, so OnError
+// may log to a Logger writing to this sink.
+func (s *Sink) cut() error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	batch := s.batch
+	s.batch, s.size = nil, 0
+	select {
+	case s.queue <- batch:
+		s.pending++
+		return nil
+	default:
+		s.dropped.Add(uint64(len(batch)))
+		return fmt.Errorf("httpsink: queue full, dropped %d entries", len(batch))
+	}
+}
+
+// report passes a non-nil error to OnError.
+func (s *Sink) report(err error) {
+	if err != nil {
+		s.options.OnError(err)
+	}
+}
+
+// Flush sends the current batch and blocks until all queued batches are
+// delivered or given up.
+func (s *Sink) Flush() {
+	var err error
+	s.mu.Lock()
+	if !s.closed && len(s.batch) > 0 {
+		err = s.cut()
+	}
+	s.mu.Unlock()
+	s.report(err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.pending > 0 {
+		s.idle.Wait()
+	}
+}
+
+// Sync flushes the sink, so Fatal delivers pending entries.
+func (s *Sink) Sync() error {
+	s.Flush()
+	return nil
+}
+
+// Close sends the remaining entries and stops the sender goroutine. If ctx
+// ends first, pending retries are abandoned and ctx.Err() is returned.
+func (s *Sink) Close(ctx context.Context) error {
+	var err error
+	s.mu.Lock()
+	if !s.closed {
+		if len(s.batch) > 0 {
+			err = s.cut()
+		}
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	s.report(err)
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.abortOnce.Do(func() { close(s.abort) })
+		<-s.done
+		return ctx.Err()
+	}
+}
+
+// Dropped returns the number of entries that were discarded because the
+// queue was full or delivery failed.
+func (s *Sink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+func (s *Sink) run() {
+	defer close(s.done)
+	for batch := range s.queue {
+		if n, err := s.send(batch); err != nil {
+			s.dropped.Add(uint64(n))
+			s.report(err)
+		}
+		s.mu.Lock()
+		s.pending--
+		if s.pending == 0 {
+			s.idle.Broadcast()
+		}
+		s.mu.Unlock()
+	}
+}
+
+// send delivers a batch, retrying temporary failures. On failure it returns
+// the number of entries that were not delivered.
+func (s *Sink) send(batch []*logging.Entry) (int, error) {
+	body, err := s.options.Format.Encode(batch)
+	if err != nil {
+		return len(batch), fmt.Errorf("httpsink: encoding batch: %w", err)
+	}
+	if s.options.Gzip {
+		var b bytes.Buffer
+		w := gzip.NewWriter(&b)
+		_, _ = w.Write(body)
+		if err := w.Close(); err != nil {
+			return len(batch), err
+		}
+		body = b.Bytes()
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := s.post(body)
+		if err == nil {
+			return 0, nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= s.options.MaxRetries {
+			n := len(batch)
+			var rejected *rejectedError
+			if errors.As(err, &rejected) {
+				n = rejected.rejected
+			}
+			return n, fmt.Errorf("httpsink: dropped %d entries: %w", n, err)
+		}
+		wait := s.backoff(attempt)
+		if retryAfter > 0 {
+			wait = min(retryAfter, s.options.MaxBackoff)
+		}
+		select {
+		case <-time.After(wait):
+		case <-s.abort:
+			return len(batch), fmt.Errorf("httpsink: dropped %d entries on close: %w", len(batch), err)
+		}
+	}
+}
+
+// permanentError is a failure retrying does not fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// post sends one request. It returns the Retry-After delay of 429 and 5xx
+// responses.
+func (s *Sink) post(body []byte) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, s.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, &permanentError{err}
+	}
+	for key, values := range s.options.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", s.options.Format.ContentType())
+	if s.options.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := s.options.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		if c, ok := s.options.Format.(responseChecker); ok {
+			if err := c.CheckResponse(respBody); err != nil {
+				return 0, &permanentError{err}
+			}
+		}
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return retryAfter(resp.Header.Get("Retry-After")), statusError(resp, respBody)
+	default:
+		return 0, &permanentError{statusError(resp, respBody)}
+	}
+}
+
+func statusError(resp *http.Response, body []byte) error {
+	return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(body))
+}
+
+// retryAfter parses the seconds or HTTP date of a Retry-After header. Dates
+// in the past give a negative delay, which is ignored like a missing header.
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// backoff returns the delay before the next attempt: doubling from
+// MinBackoff up to MaxBackoff, randomized within its upper half.
+func (s *Sink) backoff(attempt int) time.Duration {
+	d := s.options.MinBackoff
+	for i := 0; i < attempt && d < s.options.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, s.options.MaxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
@@ -0,0 +1,55 @@
+// Package netconn holds the connection handling shared by the network sinks.
+package netconn
+
+import "net"
+
+// Conn is a connection that is dialed on demand and redialed when a write
+// fails. It is not safe for concurrent use, callers hold their own lock.
+type Conn struct {
+	// Dial opens a new connection
+	Dial func() (net.Conn, error)
+
+	conn net.Conn
+}
+
+// Connect dials unless a connection is open.
+func (c *Conn) Connect() error {
+	if c.conn != nil {
+		return nil
+	}
+	conn, err := c.Dial()
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	return nil
+}
+
+// Send calls send with the connection, dialing it first if needed. A failed
+// send is retried once on a new connection.
+func (c *Conn) Send(send func(conn net.Conn) error) error {
+	if err := c.Connect(); err != nil {
+		return err
+	}
+	if err := send(c.conn); err != nil {
+		c.Close()
+		if err := c.Connect(); err != nil {
+			return err
+		}
+		if err := send(c.conn); err != nil {
+			c.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the connection. The next Send dials again.
+func (c *Conn) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
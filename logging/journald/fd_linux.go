@@ -0,0 +1,76 @@
+//go:build linux
+
+package journald
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	mfdCloexec      = 0x1
+	mfdAllowSealing = 0x2
+	fAddSeals       = 1024 + 9
+	sealAll         = 0x1 | 0x2 | 0x4 | 0x8 // seal, shrink, grow, write
+	memfdName       = "journal-entry"
+	tempDir         = "/dev/shm"
+)
+
+// sendLarge writes data to a sealed memfd and passes its descriptor, which
+// journald reads the entry from.
+func (s *Sink) sendLarge(data []byte) error {
+	f, err := memfd(data)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// WriteMsgUnix refuses connected datagram sockets, send on the raw fd
+	raw, err := s.conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sendErr error
+	err = raw.Write(func(fd uintptr) bool {
+		sendErr = syscall.Sendmsg(int(fd), nil, syscall.UnixRights(int(f.Fd())), nil, 0)
+		return sendErr != syscall.EAGAIN
+	})
+	if err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// memfd returns a sealed memfd holding data, or an unlinked temporary file
+// if memfd_create is not available.
+func memfd(data []byte) (*os.File, error) {
+	if sysMemfdCreate != 0 {
+		name, _ := syscall.BytePtrFromString(memfdName)
+		fd, _, errno := syscall.Syscall(sysMemfdCreate, uintptr(unsafe.Pointer(name)), mfdCloexec|mfdAllowSealing, 0)
+		if errno == 0 {
+			f := os.NewFile(fd, memfdName)
+			if _, err := f.Write(data); err != nil {
+				f.Close()
+				return nil, err
+			}
+			if _, _, errno := syscall.Syscall(syscall.SYS_FCNTL, fd, fAddSeals, sealAll); errno != 0 {
+				f.Close()
+				return nil, errno
+			}
+			return f, nil
+		}
+	}
+	f, err := os.CreateTemp(tempDir, "journal-")
+	if err != nil {
+		f, err = os.CreateTemp("", "journal-")
+		if err != nil {
+			return nil, err
+		}
+	}
+	os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
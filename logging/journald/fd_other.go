@@ -0,0 +1,10 @@
+//go:build !linux
+
+package journald
+
+import "errors"
+
+// sendLarge is not supported, journald only runs on Linux.
+func (s *Sink) sendLarge(data []byte) error {
+	return errors.New("journald: entry too large for a datagram")
+}
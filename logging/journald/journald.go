@@ -0,0 +1,206 @@
+// Package journald provides logging sinks for systemd-journald: a native
+// sink speaking the journal protocol and a stderr sink using the <N> level
+// prefixes of sd-daemon.
+package journald
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+// SocketPath is the native protocol socket of journald.
+const SocketPath = "/run/systemd/journal/socket"
+
+// Priority maps a log level to the syslog priority journald stores.
+func Priority(level logging.LogLevel) int {
+	switch level {
+	case logging.TRACE, logging.DEBUG:
+		return 7
+	case logging.NOTICE:
+		return 5
+	case logging.WARN:
+		return 4
+	case logging.ERROR:
+		return 3
+	case logging.FAIL:
+		return 2
+	default:
+		return 6
+	}
+}
+
+// Options configures a Sink.
+type Options struct {
+	// Socket defaults to SocketPath
+	Socket string
+	// Identifier is sent as SYSLOG_IDENTIFIER, defaults to the program name
+	Identifier string
+}
+
+// Sink sends entries to journald over the native protocol. Each entry
+// carries MESSAGE, PRIORITY, SYSLOG_IDENTIFIER, MODULE, TRACE_ID, the caller
+// as CODE_FILE, CODE_LINE and CODE_FUNC, and its fields with upper case
+// names.
+type Sink struct {
+	options Options
+	mu      sync.Mutex
+	conn    *net.UnixConn
+}
+
+// New returns a sink connected to the journal socket.
+func New(options Options) (*Sink, error) {
+	if options.Socket == "" {
+		options.Socket = SocketPath
+	}
+	if options.Identifier == "" {
+		options.Identifier = filepath.Base(os.Args[0])
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: options.Socket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{options: options, conn: conn}, nil
+}
+
+// Write sends the entry in one datagram. Entries too large for a datagram
+// are passed in a memfd, or an unlinked temporary file where memfd is not
+// available.
+func (s *Sink) Write(e *logging.Entry) error {
+	data := s.encode(e)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.conn.Write(data)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		return s.sendLarge(data)
+	}
+	return err
+}
+
+// Close closes the socket.
+func (s *Sink) Close() error {
+	return s.conn.Close()
+}
+
+func (s *Sink) encode(e *logging.Entry) []byte {
+	var b bytes.Buffer
+	writeField(&b, "MESSAGE", e.Message)
+	writeField(&b, "PRIORITY", strconv.Itoa(Priority(e.Level)))
+	writeField(&b, "SYSLOG_IDENTIFIER", s.options.Identifier)
+	if e.Module != "" {
+		writeField(&b, "MODULE", e.Module)
+	}
+	if e.Caller.File != "" {
+		writeField(&b, "CODE_FILE", e.Caller.File)
+		writeField(&b, "CODE_LINE", strconv.Itoa(e.Caller.Line))
+		writeField(&b, "CODE_FUNC", e.Caller.Function)
+	}
+	if e.Stack != "" {
+		writeField(&b, "STACK", e.Stack)
+	}
+	for _, f := range logging.FlattenFields(e.Fields) {
+		if f.Key == logging.TraceIDKey {
+			writeField(&b, "TRACE_ID", f.String())
+			continue
+		}
+		writeField(&b, FieldName(f.Key), f.String())
+	}
+	return b.Bytes()
+}
+
+// writeField appends KEY=value, or the binary form for values with newlines.
+func writeField(b *bytes.Buffer, key, value string) {
+	b.WriteString(key)
+	if !strings.ContainsRune(value, '\n') {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// reserved holds the journal fields the sink writes itself or journald
+// interprets. Field keys mapping to them are prefixed with "F_".
+var reserved = map[string]struct{}{
+	"MESSAGE": {}, "MESSAGE_ID": {}, "PRIORITY": {}, "ERRNO": {}, "DOCUMENTATION": {}, "TID": {},
+	"SYSLOG_IDENTIFIER": {}, "SYSLOG_FACILITY": {}, "SYSLOG_PID": {}, "SYSLOG_TIMESTAMP": {}, "SYSLOG_RAW": {},
+	"CODE_FILE": {}, "CODE_LINE": {}, "CODE_FUNC": {},
+	"INVOCATION_ID": {}, "USER_INVOCATION_ID": {}, "UNIT": {}, "USER_UNIT": {},
+	"MODULE": {}, "STACK": {}, "TRACE_ID": {},
+}
+
+// FieldName converts a field key to a journal field name: upper case
+// letters, digits and underscores, not starting with a digit or underscore,
+// at most 64 characters. Names the sink writes itself or journald
+// interprets, like MESSAGE or CODE_LINE, get an "F_" prefix.
+func FieldName(key string) string {
+	name := make([]byte, 0, len(key))
+	for i := 0; i < len(key) && len(name) < 64; i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			name = append(name, c-'a'+'A')
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			name = append(name, c)
+		case len(name) > 0:
+			name = append(name, '_')
+		}
+	}
+	if _, ok := reserved[string(name)]; ok || len(name) == 0 || name[0] >= '0' && name[0] <= '9' {
+		name = append([]byte("F_"), name...)
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return string(name)
+}
+
+// PrefixSink writes entries as text lines to an io.Writer, usually stderr of
+// a systemd service, prefixed with "<N>" so journald stores the priority.
+type PrefixSink struct {
+	mu      sync.Mutex
+	w       io.Writer
+	encoder logging.Encoder
+}
+
+// NewPrefixSink returns a PrefixSink. A nil encoder writes text without
+// colors and timestamps, which journald adds itself.
+func NewPrefixSink(w io.Writer, encoder logging.Encoder) *PrefixSink {
+	if encoder == nil {
+		encoder = logging.TextEncoder{DisableTextModifier: true}
+	}
+	return &PrefixSink{w: w, encoder: encoder}
+}
+
+// Write prefixes every line of the entry, including stack traces.
+func (s *PrefixSink) Write(e *logging.Entry) error {
+	line, err := s.encoder.Encode(e)
+	if err != nil {
+		return err
+	}
+	prefix := "<" + strconv.Itoa(Priority(e.Level)) + ">"
+	var b bytes.Buffer
+	for _, l := range bytes.Split(line, []byte{'\n'}) {
+		b.WriteString(prefix)
+		b.Write(l)
+		b.WriteByte('\n')
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,4 @@
+package journald
+
+// sysMemfdCreate is missing from package syscall on amd64.
+const sysMemfdCreate = 319
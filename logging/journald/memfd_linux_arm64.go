@@ -0,0 +1,5 @@
+package journald
+
+import "syscall"
+
+const sysMemfdCreate = syscall.SYS_MEMFD_CREATE
@@ -0,0 +1,6 @@
+//go:build linux && !amd64 && !arm64
+
+package journald
+
+// sysMemfdCreate is unknown, large entries go through a temporary file.
+const sysMemfdCreate = 0
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// JSONEncoder renders every entry as one JSON object with the keys time,
+// level, module and msg followed by the entry's fields. It never emits ANSI
+// codes. Pair it with a log.Logger without flags or prefix so each line is
+// valid JSON.
+type JSONEncoder struct {
+	// TimeFormat defaults to time.RFC3339Nano
+	TimeFormat string
+}
+
+func (enc JSONEncoder) Encode(e *Entry) ([]byte, error) {
+	timeFormat := enc.TimeFormat
+	if timeFormat == "" {
+		timeFormat = time.RFC3339Nano
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	if !e.Time.IsZero() {
+		writeJSONKey(&b, "time", true)
+		writeJSONString(&b, e.Time.Format(timeFormat))
+		b.WriteByte(',')
+	}
+	writeJSONKey(&b, "level", true)
+	writeJSONString(&b, strings.ToLower(levelLabel(e.Level)))
+	writeJSONKey(&b, "module", false)
+	writeJSONString(&b, e.ModuleName())
+	if caller := e.ShortCaller(); caller != "" {
+		writeJSONKey(&b, "caller", false)
+		writeJSONString(&b, caller)
+		writeJSONKey(&b, "func", false)
+		writeJSONString(&b, e.Caller.Function)
+	}
+	writeJSONKey(&b, "msg", false)
+	writeJSONString(&b, e.Message)
+	if e.Stack != "" {
+		writeJSONKey(&b, "stack", false)
+		writeJSONString(&b, e.Stack)
+	}
+	writeJSONFields(&b, e.Fields, false)
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func writeJSONFields(b *bytes.Buffer, fields []Field, first bool) {
+	for _, f := range fields {
+		writeJSONKey(b, f.Key, first)
+		writeJSONValue(b, f)
+		first = false
+	}
+}
+
+func writeJSONKey(b *bytes.Buffer, key string, first bool) {
+	if !first {
+		b.WriteByte(',')
+	}
+	writeJSONString(b, key)
+	b.WriteByte(':')
+}
+
+func writeJSONString(b *bytes.Buffer, s string) {
+	// Marshaling a string cannot fail
+	out, _ := json.Marshal(s)
+	b.Write(out)
+}
+
+// writeJSONValue keeps numbers and booleans native and falls back to the
+// text rendering for values JSON cannot represent.
+func writeJSONValue(b *bytes.Buffer, f Field) {
+	switch v := f.Value.(type) {
+	case nil:
+		b.WriteString("null")
+		return
+	case []Field:
+		b.WriteByte('{')
+		writeJSONFields(b, v, true)
+		b.WriteByte('}')
+		return
+	case string, error, time.Duration:
+		writeJSONString(b, f.String())
+		return
+	}
+	out, err := json.Marshal(f.Value)
+	if err != nil {
+		writeJSONString(b, f.String())
+		return
+	}
+	b.Write(out)
+}
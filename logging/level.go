@@ -0,0 +1,244 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// LevelEnvVar is the environment variable read by ConfigureFromEnv.
+const LevelEnvVar = "LOG_LEVEL"
+
+// levelVar holds a level that is read on every log call while it may be
+// changed concurrently, e.g. by LevelHandler.
+type levelVar struct {
+	v atomic.Int32
+}
+
+func newLevelVar(level LogLevel) *levelVar {
+	lv := &levelVar{}
+	lv.store(level)
+	return lv
+}
+
+func (lv *levelVar) load() LogLevel       { return LogLevel(lv.v.Load()) }
+func (lv *levelVar) store(level LogLevel) { lv.v.Store(int32(level)) }
+
+// inherit marks a module level that follows the level of its Logger.
+const inherit LogLevel = -1
+
+var levelNames = map[LogLevel]string{
+	TRACE:  "TRACE",
+	DEBUG:  "DEBUG",
+	INFO:   "INFO",
+	NOTICE: "NOTICE",
+	WARN:   "WARN",
+	ERROR:  "ERROR",
+	FAIL:   "FAIL",
+	NONE:   "NONE",
+}
+
+// severities orders the levels, TRACE and NOTICE have values out of order.
+var severities = map[LogLevel]int{
+	TRACE:  0,
+	DEBUG:  1,
+	INFO:   2,
+	NOTICE: 3,
+	WARN:   4,
+	ERROR:  5,
+	FAIL:   6,
+	NONE:   7,
+}
+
+// Severity returns the rank of the level: TRACE < DEBUG < INFO < NOTICE <
+// WARN < ERROR < FAIL < NONE. Use it to compare levels, the values of TRACE
+// and NOTICE are larger than NONE.
+func (level LogLevel) Severity() int {
+	if s, ok := severities[level]; ok {
+		return s
+	}
+	return severities[clampLevel(level)]
+}
+
+// clampLevel maps values that are not a level to DEBUG or NONE, the bounds
+// of the original levels.
+func clampLevel(level LogLevel) LogLevel {
+	if _, ok := levelNames[level]; ok {
+		return level
+	}
+	if level < DEBUG {
+		return DEBUG
+	}
+	return NONE
+}
+
+// String returns the upper case name of the level.
+func (level LogLevel) String() string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return "LogLevel(" + strconv.Itoa(int(level)) + ")"
+}
+
+// ParseLevel parses a level name, case insensitive. "warning" and "off" are
+// accepted for WARN and NONE, as are the numeric values of DEBUG to NONE.
+func ParseLevel(s string) (LogLevel, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	switch name {
+	case "WARNING":
+		return WARN, nil
+	case "OFF":
+		return NONE, nil
+	}
+	for level, levelName := range levelNames {
+		if name == levelName {
+			return level, nil
+		}
+	}
+	if n, err := strconv.Atoi(name); err == nil && n >= int(DEBUG) && n <= int(NONE) {
+		return LogLevel(n), nil
+	}
+	return 0, fmt.Errorf("logging: unknown log level %q", s)
+}
+
+// MarshalText returns the lower case name, for JSON and YAML configs.
+func (level LogLevel) MarshalText() ([]byte, error) {
+	if _, ok := levelNames[level]; !ok {
+		return nil, fmt.Errorf("logging: invalid log level %d", int(level))
+	}
+	return []byte(strings.ToLower(level.String())), nil
+}
+
+// UnmarshalText parses the level with ParseLevel.
+func (level *LogLevel) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*level = parsed
+	return nil
+}
+
+// Set implements flag.Value.
+func (level *LogLevel) Set(s string) error {
+	return level.UnmarshalText([]byte(s))
+}
+
+// LevelSpec is a parsed level spec like "info,Database=debug,HTTP=warn": an
+// optional level for the Logger followed by levels for single modules.
+type LevelSpec struct {
+	HasDefault bool
+	Default    LogLevel
+	Modules    map[string]LogLevel
+}
+
+// ParseLevelSpec parses a comma separated level spec.
+func ParseLevelSpec(s string) (LevelSpec, error) {
+	var spec LevelSpec
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		module, value, isModule := strings.Cut(part, "=")
+		if !isModule {
+			value = module
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			return LevelSpec{}, err
+		}
+		if !isModule {
+			spec.HasDefault = true
+			spec.Default = level
+			continue
+		}
+		module = strings.TrimSpace(module)
+		if module == "" {
+			return LevelSpec{}, fmt.Errorf("logging: missing module name in %q", part)
+		}
+		if spec.Modules == nil {
+			spec.Modules = make(map[string]LogLevel)
+		}
+		spec.Modules[module] = level
+	}
+	return spec, nil
+}
+
+// String returns the spec in the form ParseLevelSpec reads, modules sorted.
+func (spec LevelSpec) String() string {
+	var parts []string
+	if spec.HasDefault {
+		parts = append(parts, strings.ToLower(spec.Default.String()))
+	}
+	modules := make([]string, 0, len(spec.Modules))
+	for module := range spec.Modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		parts = append(parts, module+"="+strings.ToLower(spec.Modules[module].String()))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value.
+func (spec *LevelSpec) Set(s string) error {
+	parsed, err := ParseLevelSpec(s)
+	if err != nil {
+		return err
+	}
+	*spec = parsed
+	return nil
+}
+
+func (spec LevelSpec) MarshalText() ([]byte, error) {
+	return []byte(spec.String()), nil
+}
+
+func (spec *LevelSpec) UnmarshalText(text []byte) error {
+	return spec.Set(string(text))
+}
+
+// ApplyLevelSpec sets the level of the Logger and of the modules named in
+// spec. Modules that are not registered yet get their level when they are
+// created with NewSystemModuleLogger.
+func (l *Logger) ApplyLevelSpec(spec LevelSpec) {
+	if spec.HasDefault {
+		l.SetLogLevel(spec.Default)
+	}
+	l.modules.mu.Lock()
+	defer l.modules.mu.Unlock()
+	for module, level := range spec.Modules {
+		if l.modules.levels == nil {
+			l.modules.levels = make(map[string]LogLevel)
+		}
+		l.modules.levels[module] = level
+		if sm, ok := l.modules.systemModules[module]; ok {
+			sm.SetLogLevel(level)
+		}
+	}
+}
+
+// SetLevelSpec parses spec and applies it with ApplyLevelSpec.
+func (l *Logger) SetLevelSpec(spec string) error {
+	parsed, err := ParseLevelSpec(spec)
+	if err != nil {
+		return err
+	}
+	l.ApplyLevelSpec(parsed)
+	return nil
+}
+
+// ConfigureFromEnv applies the level spec in $LOG_LEVEL to the default
+// logger. An unset variable leaves the levels unchanged.
+func ConfigureFromEnv() error {
+	spec, ok := os.LookupEnv(LevelEnvVar)
+	if !ok {
+		return nil
+	}
+	return std.SetLevelSpec(spec)
+}
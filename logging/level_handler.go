@@ -0,0 +1,171 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelState is the level of the Logger or of one module as reported by
+// LevelHandler.
+type LevelState struct {
+	Name string `json:"name"`
+	// Level is the effective level
+	Level LogLevel `json:"level"`
+	// Overridden is false for modules inheriting the Logger's level
+	Overridden bool `json:"overridden"`
+	// RevertAt is set while a temporary change is active
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+// LevelsState is the response body of LevelHandler.
+type LevelsState struct {
+	Logger  LevelState   `json:"logger"`
+	Modules []LevelState `json:"modules"`
+}
+
+// LevelHandler returns an http.Handler to inspect and change levels at
+// runtime.
+//
+// GET returns the levels of the Logger and its modules as JSON. PUT and POST
+// change a level; the parameters are read from the query or a form body:
+//
+//	module  module name, the Logger itself if empty
+//	level   new level, or "reset" to inherit the Logger's level again
+//	revert  optional duration after which the previous level is restored
+//
+// The handler should be mounted behind authentication.
+func (l *Logger) LevelHandler() http.Handler {
+	return &levelHandler{logger: l, reverts: make(map[string]*pendingRevert)}
+}
+
+type levelHandler struct {
+	logger *Logger
+
+	mu      sync.Mutex
+	reverts map[string]*pendingRevert // by module name, "" for the Logger
+}
+
+// pendingRevert restores the level from before the first temporary change.
+type pendingRevert struct {
+	timer    *time.Timer
+	at       time.Time
+	previous LogLevel
+}
+
+func (h *levelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut, http.MethodPost:
+		if status, msg := h.change(r); status != http.StatusOK {
+			http.Error(w, msg, status)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.state())
+}
+
+// change applies a PUT or POST request and returns the status code and an
+// error message.
+func (h *levelHandler) change(r *http.Request) (int, string) {
+	if err := r.ParseForm(); err != nil {
+		return http.StatusBadRequest, err.Error()
+	}
+	name := r.Form.Get("module")
+	var level *levelVar
+	if name == "" || name == "General" {
+		name = ""
+		level = h.logger.level
+	} else if sm := h.logger.GetSystemModule(name); sm != nil {
+		level = sm.level
+	} else {
+		return http.StatusNotFound, "unknown module " + name
+	}
+
+	var newLevel LogLevel
+	switch value := r.Form.Get("level"); strings.ToLower(value) {
+	case "":
+		return http.StatusBadRequest, "missing level"
+	case "reset", "inherit":
+		if name == "" {
+			return http.StatusBadRequest, "the logger level cannot be reset"
+		}
+		newLevel = inherit
+	default:
+		parsed, err := ParseLevel(value)
+		if err != nil {
+			return http.StatusBadRequest, err.Error()
+		}
+		newLevel = parsed
+	}
+
+	var revert time.Duration
+	if value := r.Form.Get("revert"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return http.StatusBadRequest, "invalid revert duration " + value
+		}
+		revert = d
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	previous := level.load()
+	if p, ok := h.reverts[name]; ok {
+		// Keep reverting to the level from before the first temporary change
+		p.timer.Stop()
+		previous = p.previous
+		delete(h.reverts, name)
+	}
+	level.store(newLevel)
+	if revert > 0 {
+		p := &pendingRevert{at: time.Now().Add(revert), previous: previous}
+		p.timer = time.AfterFunc(revert, func() { h.revert(name, level, p) })
+		h.reverts[name] = p
+	}
+	return http.StatusOK, ""
+}
+
+func (h *levelHandler) revert(name string, level *levelVar, p *pendingRevert) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.reverts[name] != p {
+		return
+	}
+	delete(h.reverts, name)
+	level.store(p.previous)
+}
+
+func (h *levelHandler) state() LevelsState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state := LevelsState{
+		Logger:  LevelState{Name: "General", Level: h.logger.GetLogLevel(), Overridden: true},
+		Modules: []LevelState{},
+	}
+	if p, ok := h.reverts[""]; ok {
+		at := p.at
+		state.Logger.RevertAt = &at
+	}
+
+	h.logger.modules.mu.RLock()
+	for name, sm := range h.logger.modules.systemModules {
+		s := LevelState{Name: name, Level: sm.GetLogLevel(), Overridden: sm.LevelOverridden()}
+		if p, ok := h.reverts[name]; ok {
+			at := p.at
+			s.RevertAt = &at
+		}
+		state.Modules = append(state.Modules, s)
+	}
+	h.logger.modules.mu.RUnlock()
+	sort.Slice(state.Modules, func(i, j int) bool { return state.Modules[i].Name < state.Modules[j].Name })
+	return state
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"strings"
+	"time"
+)
+
+// LogfmtEncoder renders entries as logfmt:
+//
+//	ts=2024-05-01T12:00:00Z level=info module=Database msg=Connected db=main
+//
+// Values containing spaces, quotes, '=' or control characters are quoted and
+// escaped. It never emits ANSI codes.
+type LogfmtEncoder struct {
+	// TimeFormat defaults to time.RFC3339Nano
+	TimeFormat string
+}
+
+func (enc LogfmtEncoder) Encode(e *Entry) ([]byte, error) {
+	timeFormat := enc.TimeFormat
+	if timeFormat == "" {
+		timeFormat = time.RFC3339Nano
+	}
+
+	var b strings.Builder
+	if !e.Time.IsZero() {
+		writeLogfmtPair(&b, "ts", e.Time.Format(timeFormat))
+	}
+	writeLogfmtPair(&b, "level", strings.ToLower(levelLabel(e.Level)))
+	writeLogfmtPair(&b, "module", e.ModuleName())
+	if caller := e.ShortCaller(); caller != "" {
+		writeLogfmtPair(&b, "caller", caller)
+		writeLogfmtPair(&b, "func", e.Caller.Function)
+	}
+	writeLogfmtPair(&b, "msg", e.Message)
+	if e.Stack != "" {
+		writeLogfmtPair(&b, "stack", e.Stack)
+	}
+	for _, f := range flattenFields("", e.Fields) {
+		writeLogfmtPair(&b, f.Key, f.String())
+	}
+	return []byte(b.String()), nil
+}
+
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+	b.WriteString(quoteIfNeeded(value))
+}
+
+// logfmtKey replaces characters that cannot appear in an unquoted key.
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '"' || r == '=' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, key)
+}
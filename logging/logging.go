@@ -1,9 +1,14 @@
 package logging
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -13,6 +18,10 @@ const (
 	ERROR
 	FAIL
 	NONE
+	// TRACE and NOTICE are appended so the values above keep their meaning.
+	// Compare levels with Severity.
+	TRACE
+	NOTICE
 )
 
 type LogLevel int
@@ -36,179 +45,386 @@ type LoggerInterface interface {
 
 // Logger structure
 type Logger struct {
-	level               LogLevel
+	*pipeline
+	level               *levelVar
 	logger              *log.Logger
-	systemModules       map[string]*SystemModuleLogger
+	root                *Logger // the Logger created by NewLogger, owns the modules
+	modules             *moduleRegistry
+	sinks               *sinkSet
+	hooks               *hookSet
+	fields              []Field
+	encoder             Encoder
+	handler             slog.Handler
+	colorMode           ColorMode
+	theme               *Theme
+	exit                func(code int)
+	terminal            *terminalCache
 	DisableTextModifier bool
 }
 
+// pipeline holds the processing features enabled on a Logger. It is shared
+// with the children created by With, so features enabled later apply to
+// them too.
+// The features that can be replaced while other goroutines log are held in
+// atomic pointers.
+type pipeline struct {
+	async      atomic.Pointer[asyncQueue]
+	dedup      atomic.Pointer[dedupFilter]
+	limiter    atomic.Pointer[rateLimiter]
+	redactor   atomic.Pointer[redactor]
+	caller     bool
+	stacktrace bool
+	stackLevel LogLevel
+	onceKeys   sync.Map // keys logged by the *Once helpers
+}
+
+// moduleRegistry holds the SystemModuleLoggers of a Logger. Modules created
+// through a child made with With are registered on the root Logger too.
+type moduleRegistry struct {
+	mu            sync.RWMutex
+	systemModules map[string]*SystemModuleLogger
+	// levels set by ApplyLevelSpec, also for modules not created yet
+	levels map[string]LogLevel
+}
+
 var std *Logger = NewLogger(log.Default(), INFO)
 
 func Default() *Logger { return std }
 
 // New logger constructor
 func NewLogger(logLogger *log.Logger, level LogLevel) *Logger {
-	return &Logger{
-		level:  level,
-		logger: logLogger,
+	l := &Logger{
+		pipeline: &pipeline{},
+		level:    newLevelVar(clampLevel(level)),
+		logger:   logLogger,
+		modules:  &moduleRegistry{systemModules: make(map[string]*SystemModuleLogger)},
+		sinks:    &sinkSet{},
+		hooks:    &hookSet{},
+		terminal: &terminalCache{},
 	}
+	l.root = l
+	return l
 }
+
+// With returns a child logger that adds fields to every entry. The child
+// shares the log level, system modules, sinks, hooks and the features
+// enabled with EnableCaller, EnableAsync, EnableDedup, EnableRateLimit and
+// EnableRedaction, also when they are enabled later. Output settings like
+// the encoder, theme and color mode are copied.
+func (l *Logger) With(fields ...Field) *Logger {
+	child := *l
+	child.fields = joinFields(l.fields, fields)
+	return &child
+}
+
 func (l *Logger) SetLogLevel(logLevel LogLevel) {
-	l.level = max(min(logLevel, 5), 0)
+	l.level.store(clampLevel(logLevel))
 }
 func (l *Logger) GetLogLevel() LogLevel {
-	return l.level
+	return l.level.load()
 }
+
+// SetLogger sets the log.Logger entries are written to. It replaces a
+// slog.Handler set by NewSlogLogger.
 func (l *Logger) SetLogger(logLogger *log.Logger) {
 	if logLogger != nil {
 		l.logger = logLogger
+		l.handler = nil
+		l.terminal.reset()
 	}
 }
-func (l *Logger) prefixGenerator(color TextModifier, level string, module *SystemModuleLogger, textColor TextModifier) string {
 
-	// Save the original prefix
-	originalPrefix := l.logger.Prefix()
+// SetEncoder sets the encoder used to turn entries into lines. nil restores
+// the default TextEncoder.
+func (l *Logger) SetEncoder(encoder Encoder) {
+	l.encoder = encoder
+}
+
+// encode renders an entry with the configured encoder, or with the text
+// layout when none is set. The text layout is colored depending on the color
+// mode and the output of the log.Logger.
+func (l *Logger) encode(e *Entry) ([]byte, error) {
+	if l.encoder != nil {
+		return l.encoder.Encode(e)
+	}
+	enc := TextEncoder{DisableTextModifier: !l.colored(l.logger.Writer()), Theme: l.theme}
+	if !enc.DisableTextModifier {
+		enc.ColorDepth = l.terminal.colorDepth()
+	}
+	return enc.Encode(e)
+}
 
+// newEntry builds the entry for a log call, merging the fields of the
+// Logger, the module and the call.
+func (l *Logger) newEntry(level LogLevel, module *SystemModuleLogger, message string, fields []Field) *Entry {
+	e := &Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+	}
 	if module != nil {
-		if module.NameColor == "" {
-			textColor = Reset
-		}
-		if textColor == "" {
-			if module.TextColor == "" {
-				textColor = Reset
-			} else {
-				textColor = module.TextColor
-			}
-		}
-		if l.DisableTextModifier {
-			// Set new prefix with color and level and module
-			l.logger.SetPrefix(fmt.Sprintf("[%s]\t[%s]\t", level, module.ModuleName))
-		} else {
-			// Set new prefix with color and level and module
-			l.logger.SetPrefix(fmt.Sprintf("%s[%s]%s\t[%s]%s\t", color, level, module.NameColor, module.ModuleName, textColor))
-		}
+		e.Module = module.ModuleName
+		e.NameColor = module.NameColor
+		e.TextColor = module.TextColor
+		e.Fields = joinFields(l.fields, module.fields, fields)
 	} else {
-		if textColor == "" {
-			textColor = Reset
+		e.Fields = joinFields(l.fields, fields)
+	}
+	return e
+}
+
+// enabled reports whether an entry at level passes the level of the Logger
+// or module and has a log.Logger or slog.Handler to go to, or is accepted
+// by at least one sink. Sinks have their own levels, so they can receive
+// entries below the level of the Logger.
+func (l *Logger) enabled(level LogLevel, module *SystemModuleLogger) bool {
+	if module != nil {
+		if level.Severity() >= module.GetLogLevel().Severity() && (l.logger != nil || l.handler != nil) {
+			return true
 		}
-		if l.DisableTextModifier {
-			// Set new prefix with color and level
-			l.logger.SetPrefix(fmt.Sprintf("[%s]\t[General]\t", level))
-		} else {
-			// Set new prefix with color and level
-			l.logger.SetPrefix(fmt.Sprintf("%s[%s]%s\t[General]\t", color, level, textColor))
+		return l.sinks.accepts(level, module.ModuleName)
+	}
+	if level.Severity() >= l.level.load().Severity() && (l.logger != nil || l.handler != nil) {
+		return true
+	}
+	return l.sinks.accepts(level, "General")
+}
+
+// primaryEnabled reports whether an entry passes the level of the Logger or
+// of its module, which applies to the log.Logger or slog.Handler.
+func (l *Logger) primaryEnabled(e *Entry) bool {
+	level := l.level.load()
+	if e.Module != "" {
+		l.modules.mu.RLock()
+		sm := l.modules.systemModules[e.Module]
+		l.modules.mu.RUnlock()
+		if sm != nil {
+			level = sm.GetLogLevel()
 		}
 	}
-	return originalPrefix
+	return e.Level.Severity() >= level.Severity()
 }
 
-// Helper function to log messages with color and level
-func (l *Logger) logWithLevel(color TextModifier, level string, module *SystemModuleLogger, textColor TextModifier, msg ...string) {
-	if l.logger == nil {
+// write applies deduplication and rate limiting and passes the entry on.
+func (l *Logger) write(e *Entry) {
+	if dedup := l.dedup.Load(); dedup != nil && !dedup.allow(l, e) {
 		return
 	}
-	originalPrefix := l.prefixGenerator(color, level, module, textColor)
-	message := strings.Join(msg, " ")
-	if !l.DisableTextModifier {
-		message += string(Reset)
+	if limiter := l.limiter.Load(); limiter != nil && !limiter.allow(e) {
+		return
 	}
-	// Log the message
-	l.logger.Println(message)
-
-	// Restore the original prefix
-	l.logger.SetPrefix(originalPrefix)
+	l.dispatch(e)
 }
-func (l *Logger) logWithLevelF(color TextModifier, level string, module *SystemModuleLogger, textColor TextModifier, format string, v ...any) {
-	if l.logger == nil {
+
+// dispatch queues the entry when the Logger is asynchronous and writes it
+// right away otherwise.
+func (l *Logger) dispatch(e *Entry) {
+	if q := l.async.Load(); q != nil && q.enqueue(l, e) {
 		return
 	}
-	originalPrefix := l.prefixGenerator(color, level, module, textColor)
+	l.writeNow(e)
+}
 
-	message := fmt.Sprintf(format, v...)
-	if !l.DisableTextModifier {
-		message += string(Reset)
+// writeNow hands a finished entry to the slog.Handler or the wrapped
+// log.Logger, if it passes the level of the Logger or module, and to the
+// sinks. The line is built independently for every entry and Output takes
+// the log.Logger's own lock, so it is written in a single call.
+func (l *Logger) writeNow(e *Entry) {
+	if l.handler != nil && l.primaryEnabled(e) {
+		writeSlog(l.handler, e)
+	} else if l.logger != nil && l.primaryEnabled(e) {
+		if line, err := l.encode(e); err == nil {
+			writeStdlog(l.logger, e, line)
+		}
 	}
-	// Log the message
-	l.logger.Println(message)
+	l.sinks.write(e)
+}
+
+// output builds the entry of a log call and passes it on. ctx is nil
+// unless the call was made with a context.
+func (l *Logger) output(ctx context.Context, level LogLevel, module *SystemModuleLogger, message string, fields []Field) {
+	e := l.newEntry(level, module, message, fields)
+	e.ctx = ctx
+	if pc, stack := l.needsCallers(level); pc {
+		// skip callers, output, logWithLevel* and Debug/Info/...
+		l.annotate(e, callers(3, stack), stack)
+	}
+	l.process(e, module)
+}
+
+// Helper function to log messages with level
+func (l *Logger) logWithLevel(level LogLevel, module *SystemModuleLogger, msg ...string) {
+	l.output(nil, level, module, strings.Join(msg, " "), nil)
+}
+func (l *Logger) logWithLevelF(level LogLevel, module *SystemModuleLogger, format string, v ...any) {
+	l.output(nil, level, module, fmt.Sprintf(format, v...), nil)
+}
+func (l *Logger) logWithLevelW(level LogLevel, module *SystemModuleLogger, msg string, fields ...Field) {
+	l.output(nil, level, module, msg, fields)
+}
+func (l *Logger) logWithLevelCtx(ctx context.Context, level LogLevel, module *SystemModuleLogger, msg ...string) {
+	l.output(ctx, level, module, strings.Join(msg, " "), FieldsFromContext(ctx))
+}
+func (l *Logger) logWithLevelFCtx(ctx context.Context, level LogLevel, module *SystemModuleLogger, format string, v ...any) {
+	l.output(ctx, level, module, fmt.Sprintf(format, v...), FieldsFromContext(ctx))
+}
 
-	// Restore the original prefix
-	l.logger.SetPrefix(originalPrefix)
+// Trace level log with gray color
+func (l *Logger) Trace(msg ...string) {
+	if l.enabled(TRACE, nil) {
+		l.logWithLevel(TRACE, nil, msg...)
+	}
 }
 
 // Debug level log with blue color
 func (l *Logger) Debug(msg ...string) {
-	if l.level <= DEBUG {
-		l.logWithLevel(Blue, "DEBUG", nil, "", msg...)
+	if l.enabled(DEBUG, nil) {
+		l.logWithLevel(DEBUG, nil, msg...)
 	}
 }
 
 // Info level log with green color
 func (l *Logger) Info(msg ...string) {
-	if l.level <= INFO {
-		l.logWithLevel(Green, "INFO", nil, "", msg...)
+	if l.enabled(INFO, nil) {
+		l.logWithLevel(INFO, nil, msg...)
+	}
+}
+
+// Notice level log with cyan color
+func (l *Logger) Notice(msg ...string) {
+	if l.enabled(NOTICE, nil) {
+		l.logWithLevel(NOTICE, nil, msg...)
 	}
 }
 
 // Warn level log with yellow color
 func (l *Logger) Warn(msg ...string) {
-	if l.level <= WARN {
-		l.logWithLevel(Yellow, "WARN", nil, "", msg...)
+	if l.enabled(WARN, nil) {
+		l.logWithLevel(WARN, nil, msg...)
 	}
 }
 
 // Error level log with red color
 func (l *Logger) Error(msg ...string) {
-	if l.level <= ERROR {
-		l.logWithLevel(Red, "ERROR", nil, "", msg...)
+	if l.enabled(ERROR, nil) {
+		l.logWithLevel(ERROR, nil, msg...)
 	}
 }
 
 // Error level log with red color
 func (l *Logger) Fail(msg ...string) {
-	if l.level <= FAIL {
-		l.logWithLevel(Red+MagentaBG, "FAIL", nil, Red+MagentaBG, msg...)
+	if l.enabled(FAIL, nil) {
+		l.logWithLevel(FAIL, nil, msg...)
+	}
+}
+
+// Trace level log with gray color
+func (l *Logger) TraceF(format string, v ...any) {
+	if l.enabled(TRACE, nil) {
+		l.logWithLevelF(TRACE, nil, format, v...)
 	}
 }
 
 // Debug level log with blue color
 func (l *Logger) DebugF(format string, v ...any) {
-	if l.level <= DEBUG {
-		l.logWithLevelF(Blue, "DEBUG", nil, "", format, v...)
+	if l.enabled(DEBUG, nil) {
+		l.logWithLevelF(DEBUG, nil, format, v...)
 	}
 }
 
 // Info level log with green color
 func (l *Logger) InfoF(format string, v ...any) {
-	if l.level <= INFO {
-		l.logWithLevelF(Green, "INFO", nil, "", format, v...)
+	if l.enabled(INFO, nil) {
+		l.logWithLevelF(INFO, nil, format, v...)
+	}
+}
+
+// Notice level log with cyan color
+func (l *Logger) NoticeF(format string, v ...any) {
+	if l.enabled(NOTICE, nil) {
+		l.logWithLevelF(NOTICE, nil, format, v...)
 	}
 }
 
 // Warn level log with yellow color
 func (l *Logger) WarnF(format string, v ...any) {
-	if l.level <= WARN {
-		l.logWithLevelF(Yellow, "WARN", nil, "", format, v...)
+	if l.enabled(WARN, nil) {
+		l.logWithLevelF(WARN, nil, format, v...)
 	}
 }
 
 // Error level log with red color
 func (l *Logger) ErrorF(format string, v ...any) {
-	if l.level <= ERROR {
-		l.logWithLevelF(Red, "ERROR", nil, "", format, v...)
+	if l.enabled(ERROR, nil) {
+		l.logWithLevelF(ERROR, nil, format, v...)
 	}
 }
 
 // Error level log with red color
 func (l *Logger) FailF(format string, v ...any) {
-	if l.level <= FAIL {
-		l.logWithLevelF(Red+MagentaBG, "FAIL", nil, Red+MagentaBG, format, v...)
+	if l.enabled(FAIL, nil) {
+		l.logWithLevelF(FAIL, nil, format, v...)
+	}
+}
+
+// Trace level log with fields
+func (l *Logger) TraceW(msg string, fields ...Field) {
+	if l.enabled(TRACE, nil) {
+		l.logWithLevelW(TRACE, nil, msg, fields...)
+	}
+}
+
+// Debug level log with fields
+func (l *Logger) DebugW(msg string, fields ...Field) {
+	if l.enabled(DEBUG, nil) {
+		l.logWithLevelW(DEBUG, nil, msg, fields...)
+	}
+}
+
+// Info level log with fields
+func (l *Logger) InfoW(msg string, fields ...Field) {
+	if l.enabled(INFO, nil) {
+		l.logWithLevelW(INFO, nil, msg, fields...)
+	}
+}
+
+// Notice level log with fields
+func (l *Logger) NoticeW(msg string, fields ...Field) {
+	if l.enabled(NOTICE, nil) {
+		l.logWithLevelW(NOTICE, nil, msg, fields...)
+	}
+}
+
+// Warn level log with fields
+func (l *Logger) WarnW(msg string, fields ...Field) {
+	if l.enabled(WARN, nil) {
+		l.logWithLevelW(WARN, nil, msg, fields...)
+	}
+}
+
+// Error level log with fields
+func (l *Logger) ErrorW(msg string, fields ...Field) {
+	if l.enabled(ERROR, nil) {
+		l.logWithLevelW(ERROR, nil, msg, fields...)
 	}
 }
 
+// Fail level log with fields
+func (l *Logger) FailW(msg string, fields ...Field) {
+	if l.enabled(FAIL, nil) {
+		l.logWithLevelW(FAIL, nil, msg, fields...)
+	}
+}
+
+// Printf logs without a level check. The entry has level NONE and is
+// printed as "????".
 func (l *Logger) Printf(format string, v ...any) {
-	l.logWithLevelF("", "????", nil, "", format, v...)
+	l.logWithLevelF(NONE, nil, format, v...)
 }
 
+// Println logs at DEBUG level
 func (l *Logger) Println(msg ...string) {
-	l.Debug(msg...)
+	if l.enabled(DEBUG, nil) {
+		l.logWithLevel(DEBUG, nil, msg...)
+	}
 }
@@ -0,0 +1,140 @@
+// Package logtest captures log entries in memory so tests can assert on
+// levels, modules, messages and fields instead of matching colored output.
+package logtest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+// Recorder is a logging.Sink that keeps a copy of every entry.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []*logging.Entry
+}
+
+// New returns a Logger that logs every level into a Recorder and nowhere
+// else.
+func New() (*logging.Logger, *Recorder) {
+	rec := &Recorder{}
+	logger := logging.NewLogger(nil, logging.TRACE)
+	logger.AddSink(rec, logging.SinkOptions{Level: logging.TRACE})
+	return logger, rec
+}
+
+// NewT is like New but also writes every entry to t.Log, so the output is
+// shown next to a failing test.
+func NewT(t testing.TB) (*logging.Logger, *Recorder) {
+	logger, rec := New()
+	logger.AddSink(NewTestSink(t), logging.SinkOptions{Level: logging.TRACE})
+	return logger, rec
+}
+
+func (r *Recorder) Write(e *logging.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e.Clone())
+	return nil
+}
+
+// Entries returns the recorded entries in the order they were logged.
+func (r *Recorder) Entries() []*logging.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*logging.Entry(nil), r.entries...)
+}
+
+// Reset discards the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// Find returns the entries with the given level and module whose message
+// contains substring. An empty module matches every module, "General" the
+// entries of the Logger itself.
+func (r *Recorder) Find(level logging.LogLevel, module, substring string) []*logging.Entry {
+	var found []*logging.Entry
+	for _, e := range r.Entries() {
+		if e.Level == level && (module == "" || e.ModuleName() == module) && strings.Contains(e.Message, substring) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// RequireLogged fails the test immediately unless an entry matching Find was
+// recorded.
+func (r *Recorder) RequireLogged(t testing.TB, level logging.LogLevel, module, substring string) *logging.Entry {
+	t.Helper()
+	found := r.Find(level, module, substring)
+	if len(found) == 0 {
+		t.Fatalf("no %s entry of module %q containing %q, recorded:\n%s", level, module, substring, r.dump())
+		return nil
+	}
+	return found[0]
+}
+
+// NoEntriesAbove reports an error for every entry with a level higher than
+// level. Printf entries, which have no level, are ignored.
+func (r *Recorder) NoEntriesAbove(t testing.TB, level logging.LogLevel) {
+	t.Helper()
+	for _, e := range r.Entries() {
+		if e.Level.Severity() > level.Severity() && e.Level != logging.NONE {
+			t.Errorf("unexpected %s entry: %s", e.Level, format(e))
+		}
+	}
+}
+
+// dump lists the recorded entries for failure messages.
+func (r *Recorder) dump() string {
+	var b strings.Builder
+	for _, e := range r.Entries() {
+		b.WriteString("\t")
+		b.WriteString(format(e))
+		b.WriteByte('\n')
+	}
+	if b.Len() == 0 {
+		return "\t(none)\n"
+	}
+	return b.String()
+}
+
+var plain = logging.TextEncoder{DisableTextModifier: true}
+
+func format(e *logging.Entry) string {
+	line, _ := plain.Encode(e)
+	return string(line)
+}
+
+// testSink writes entries to t.Log until the test has finished.
+type testSink struct {
+	mu   sync.Mutex
+	t    testing.TB
+	done bool
+}
+
+// NewTestSink returns a sink writing entries without colors to t.Log. Entries
+// logged after the test finished, e.g. by leftover goroutines, are dropped.
+func NewTestSink(t testing.TB) logging.Sink {
+	s := &testSink{t: t}
+	t.Cleanup(func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.done = true
+	})
+	return s
+}
+
+func (s *testSink) Write(e *logging.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.done {
+		s.t.Log(format(e))
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package logging
+
+// once reports whether key is logged for the first time on the Logger, its
+// children and its modules.
+func (l *Logger) once(key string) bool {
+	_, loaded := l.onceKeys.LoadOrStore(key, struct{}{})
+	return !loaded
+}
+
+// Info level log, only the first time key is used on the Logger
+func (l *Logger) InfoOnce(key string, msg ...string) {
+	if l.enabled(INFO, nil) && l.once(key) {
+		l.logWithLevel(INFO, nil, msg...)
+	}
+}
+
+// Warn level log, only the first time key is used on the Logger
+func (l *Logger) WarnOnce(key string, msg ...string) {
+	if l.enabled(WARN, nil) && l.once(key) {
+		l.logWithLevel(WARN, nil, msg...)
+	}
+}
+
+// Error level log, only the first time key is used on the Logger
+func (l *Logger) ErrorOnce(key string, msg ...string) {
+	if l.enabled(ERROR, nil) && l.once(key) {
+		l.logWithLevel(ERROR, nil, msg...)
+	}
+}
+
+// Info level log, only the first time key is used on the module's Logger
+func (sm *SystemModuleLogger) InfoOnce(key string, msg ...string) {
+	if sm.logger.enabled(INFO, sm) && sm.logger.once(key) {
+		sm.logger.logWithLevel(INFO, sm, msg...)
+	}
+}
+
+// Warn level log, only the first time key is used on the module's Logger
+func (sm *SystemModuleLogger) WarnOnce(key string, msg ...string) {
+	if sm.logger.enabled(WARN, sm) && sm.logger.once(key) {
+		sm.logger.logWithLevel(WARN, sm, msg...)
+	}
+}
+
+// Error level log, only the first time key is used on the module's Logger
+func (sm *SystemModuleLogger) ErrorOnce(key string, msg ...string) {
+	if sm.logger.enabled(ERROR, sm) && sm.logger.once(key) {
+		sm.logger.logWithLevel(ERROR, sm, msg...)
+	}
+}
+
+// Info level log, only the first time key is used on the default logger
+func InfoOnce(key string, msg ...string) {
+	if std.enabled(INFO, nil) && std.once(key) {
+		std.logWithLevel(INFO, nil, msg...)
+	}
+}
+
+// Warn level log, only the first time key is used on the default logger
+func WarnOnce(key string, msg ...string) {
+	if std.enabled(WARN, nil) && std.once(key) {
+		std.logWithLevel(WARN, nil, msg...)
+	}
+}
+
+// Error level log, only the first time key is used on the default logger
+func ErrorOnce(key string, msg ...string) {
+	if std.enabled(ERROR, nil) && std.once(key) {
+		std.logWithLevel(ERROR, nil, msg...)
+	}
+}
@@ -0,0 +1,308 @@
+// Package otlp exports entries as OpenTelemetry log records over OTLP/HTTP.
+// Batching and retries are provided by httpsink.
+package otlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/httpsink"
+)
+
+// DefaultEndpoint is the logs endpoint of a local collector.
+const DefaultEndpoint = "http://localhost:4318/v1/logs"
+
+// Encoding of request bodies.
+type Encoding int
+
+const (
+	JSON Encoding = iota
+	Protobuf
+)
+
+// SeverityNumber maps a log level to the OpenTelemetry severity number.
+// NONE, the level of Printf, maps to SEVERITY_NUMBER_UNSPECIFIED.
+func SeverityNumber(level logging.LogLevel) int {
+	switch level {
+	case logging.NONE:
+		return 0
+	case logging.TRACE:
+		return 1
+	case logging.DEBUG:
+		return 5
+	case logging.NOTICE:
+		return 10 // INFO2
+	case logging.WARN:
+		return 13
+	case logging.ERROR:
+		return 17
+	case logging.FAIL:
+		return 21 // FATAL
+	default:
+		return 9
+	}
+}
+
+// Format encodes batches as OTLP ExportLogsServiceRequest. Records are
+// grouped into one instrumentation scope per module, named after it.
+//
+// The fields "trace_id" and "span_id", set by logging.ContextWithTraceID,
+// logging.ContextWithSpanID and CustomError.Log, become the trace context of
+// a record if they are hex encoded. 64-bit trace IDs like those of
+// CustomError are zero padded to 128 bits.
+type Format struct {
+	Encoding Encoding
+	// ServiceName is the service.name resource attribute
+	ServiceName string
+	// ResourceAttributes are added to the resource, e.g.
+	// {"deployment.environment": "prod"}
+	ResourceAttributes map[string]string
+}
+
+func (f Format) ContentType() string {
+	if f.Encoding == Protobuf {
+		return "application/x-protobuf"
+	}
+	return "application/json"
+}
+
+func (f Format) Encode(entries []*logging.Entry) ([]byte, error) {
+	req := f.request(entries)
+	if f.Encoding == Protobuf {
+		return req.marshalProto(), nil
+	}
+	return json.Marshal(req)
+}
+
+// Options configures New.
+type Options struct {
+	// Endpoint defaults to $OTEL_EXPORTER_OTLP_LOGS_ENDPOINT,
+	// $OTEL_EXPORTER_OTLP_ENDPOINT + "/v1/logs" or DefaultEndpoint
+	Endpoint string
+	Encoding Encoding
+	// ServiceName defaults to $OTEL_SERVICE_NAME or the program name
+	ServiceName        string
+	ResourceAttributes map[string]string
+	// Header is added to every request, e.g. for authentication
+	Header http.Header
+	// Batch configures batching and retries; its URL, Format and Header are
+	// set from the fields above
+	Batch httpsink.Options
+}
+
+// New returns a sink exporting to an OTLP/HTTP endpoint. Close it on
+// shutdown to send the remaining records.
+func New(options Options) (*httpsink.Sink, error) {
+	endpoint := options.Endpoint
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT")
+	}
+	if endpoint == "" {
+		if base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); base != "" {
+			endpoint = strings.TrimSuffix(base, "/") + "/v1/logs"
+		}
+	}
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	serviceName := options.ServiceName
+	if serviceName == "" {
+		serviceName = os.Getenv("OTEL_SERVICE_NAME")
+	}
+	if serviceName == "" {
+		serviceName = filepath.Base(os.Args[0])
+	}
+	batch := options.Batch
+	batch.URL = endpoint
+	batch.Header = options.Header
+	batch.Format = Format{Encoding: options.Encoding, ServiceName: serviceName, ResourceAttributes: options.ResourceAttributes}
+	return httpsink.New(batch)
+}
+
+// The types below mirror the OTLP protobuf messages; their JSON encoding is
+// the OTLP/JSON mapping.
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	TimeUnixNano         uint64     `json:"timeUnixNano,string"`
+	ObservedTimeUnixNano uint64     `json:"observedTimeUnixNano,string"`
+	SeverityNumber       int        `json:"severityNumber,omitempty"`
+	SeverityText         string     `json:"severityText,omitempty"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+	TraceID              string     `json:"traceId,omitempty"`
+	SpanID               string     `json:"spanId,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// anyValue holds exactly one of its fields.
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *int64   `json:"intValue,omitempty,string"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func stringValue(s string) anyValue {
+	return anyValue{StringValue: &s}
+}
+
+func (f Format) request(entries []*logging.Entry) exportRequest {
+	attributes := []keyValue{{Key: "service.name", Value: stringValue(f.ServiceName)}}
+	keys := make([]string, 0, len(f.ResourceAttributes))
+	for key := range f.ResourceAttributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key != "service.name" {
+			attributes = append(attributes, keyValue{Key: key, Value: stringValue(f.ResourceAttributes[key])})
+		}
+	}
+
+	observed := uint64(time.Now().UnixNano())
+	var scopes []scopeLogs
+	index := map[string]int{}
+	for _, e := range entries {
+		module := e.ModuleName()
+		i, ok := index[module]
+		if !ok {
+			i = len(scopes)
+			index[module] = i
+			scopes = append(scopes, scopeLogs{Scope: scope{Name: module}})
+		}
+		scopes[i].LogRecords = append(scopes[i].LogRecords, record(e, observed))
+	}
+	return exportRequest{ResourceLogs: []resourceLogs{{Resource: resource{Attributes: attributes}, ScopeLogs: scopes}}}
+}
+
+func record(e *logging.Entry, observed uint64) logRecord {
+	r := logRecord{
+		ObservedTimeUnixNano: observed,
+		SeverityNumber:       SeverityNumber(e.Level),
+		Body:                 stringValue(e.Message),
+	}
+	if e.Level != logging.NONE {
+		r.SeverityText = e.Level.String()
+	}
+	if !e.Time.IsZero() {
+		r.TimeUnixNano = uint64(e.Time.UnixNano())
+	}
+	if e.Caller.File != "" {
+		line := int64(e.Caller.Line)
+		r.Attributes = append(r.Attributes,
+			keyValue{Key: "code.filepath", Value: stringValue(e.Caller.File)},
+			keyValue{Key: "code.lineno", Value: anyValue{IntValue: &line}},
+			keyValue{Key: "code.function", Value: stringValue(e.Caller.Function)})
+	}
+	if e.Stack != "" {
+		r.Attributes = append(r.Attributes, keyValue{Key: "code.stacktrace", Value: stringValue(e.Stack)})
+	}
+	for _, field := range logging.FlattenFields(e.Fields) {
+		switch field.Key {
+		case logging.TraceIDKey:
+			if id, ok := hexID(field.String(), 16); ok && r.TraceID == "" {
+				r.TraceID = id
+				continue
+			}
+		case logging.SpanIDKey:
+			if id, ok := hexID(field.String(), 8); ok && r.SpanID == "" {
+				r.SpanID = id
+				continue
+			}
+		}
+		r.Attributes = append(r.Attributes, keyValue{Key: field.Key, Value: value(field)})
+	}
+	return r
+}
+
+// hexID returns id as lower case hex of size bytes, left padding shorter
+// IDs with zeros. IDs that are not hex, too long or all zero are rejected.
+func hexID(id string, size int) (string, bool) {
+	b, err := hex.DecodeString(id)
+	if err != nil || len(b) == 0 || len(b) > size {
+		return "", false
+	}
+	for _, c := range b {
+		if c != 0 {
+			return strings.Repeat("00", size-len(b)) + hex.EncodeToString(b), true
+		}
+	}
+	return "", false
+}
+
+// value keeps booleans and numbers typed.
+func value(f logging.Field) anyValue {
+	switch v := f.Value.(type) {
+	case bool:
+		return anyValue{BoolValue: &v}
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		n := toInt64(v)
+		return anyValue{IntValue: &n}
+	case float32:
+		d := float64(v)
+		if !math.IsInf(d, 0) && !math.IsNaN(d) {
+			return anyValue{DoubleValue: &d}
+		}
+	case float64:
+		if !math.IsInf(v, 0) && !math.IsNaN(v) {
+			return anyValue{DoubleValue: &v}
+		}
+	}
+	return stringValue(f.String())
+}
+
+func toInt64(v any) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int8:
+		return int64(n)
+	case int16:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case uint8:
+		return int64(n)
+	case uint16:
+		return int64(n)
+	case uint32:
+		return int64(n)
+	}
+	return 0
+}
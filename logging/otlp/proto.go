@@ -0,0 +1,120 @@
+package otlp
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// protoBuffer appends protobuf fields. Only the subset of the wire format
+// the OTLP logs messages need is implemented.
+type protoBuffer []byte
+
+func (b *protoBuffer) tag(field, wireType int) {
+	*b = binary.AppendUvarint(*b, uint64(field)<<3|uint64(wireType))
+}
+
+func (b *protoBuffer) varint(field int, v uint64) {
+	b.tag(field, wireVarint)
+	*b = binary.AppendUvarint(*b, v)
+}
+
+func (b *protoBuffer) fixed64(field int, v uint64) {
+	b.tag(field, wireFixed64)
+	*b = binary.LittleEndian.AppendUint64(*b, v)
+}
+
+func (b *protoBuffer) bytes(field int, v []byte) {
+	b.tag(field, wireBytes)
+	*b = binary.AppendUvarint(*b, uint64(len(v)))
+	*b = append(*b, v...)
+}
+
+func (b *protoBuffer) string(field int, v string) {
+	b.tag(field, wireBytes)
+	*b = binary.AppendUvarint(*b, uint64(len(v)))
+	*b = append(*b, v...)
+}
+
+// message appends an embedded message written by fn.
+func (b *protoBuffer) message(field int, fn func(m *protoBuffer)) {
+	var m protoBuffer
+	fn(&m)
+	b.bytes(field, m)
+}
+
+// marshalProto encodes the request as
+// opentelemetry.proto.collector.logs.v1.ExportLogsServiceRequest.
+func (req exportRequest) marshalProto() []byte {
+	var b protoBuffer
+	for _, rl := range req.ResourceLogs {
+		b.message(1, func(m *protoBuffer) {
+			m.message(1, func(r *protoBuffer) {
+				for _, kv := range rl.Resource.Attributes {
+					r.message(1, kv.marshalProto)
+				}
+			})
+			for _, sl := range rl.ScopeLogs {
+				m.message(2, func(s *protoBuffer) {
+					s.message(1, func(sc *protoBuffer) { sc.string(1, sl.Scope.Name) })
+					for _, r := range sl.LogRecords {
+						s.message(2, r.marshalProto)
+					}
+				})
+			}
+		})
+	}
+	return b
+}
+
+func (r logRecord) marshalProto(b *protoBuffer) {
+	if r.TimeUnixNano != 0 {
+		b.fixed64(1, r.TimeUnixNano)
+	}
+	if r.SeverityNumber != 0 {
+		b.varint(2, uint64(r.SeverityNumber))
+	}
+	if r.SeverityText != "" {
+		b.string(3, r.SeverityText)
+	}
+	b.message(5, r.Body.marshalProto)
+	for _, kv := range r.Attributes {
+		b.message(6, kv.marshalProto)
+	}
+	if id, err := hex.DecodeString(r.TraceID); err == nil && len(id) > 0 {
+		b.bytes(9, id)
+	}
+	if id, err := hex.DecodeString(r.SpanID); err == nil && len(id) > 0 {
+		b.bytes(10, id)
+	}
+	b.fixed64(11, r.ObservedTimeUnixNano)
+}
+
+func (kv keyValue) marshalProto(b *protoBuffer) {
+	b.string(1, kv.Key)
+	b.message(2, kv.Value.marshalProto)
+}
+
+func (v anyValue) marshalProto(b *protoBuffer) {
+	switch {
+	case v.StringValue != nil:
+		b.string(1, *v.StringValue)
+	case v.BoolValue != nil:
+		var n uint64
+		if *v.BoolValue {
+			n = 1
+		}
+		b.varint(2, n)
+	case v.IntValue != nil:
+		b.varint(3, uint64(*v.IntValue))
+	case v.DoubleValue != nil:
+		b.fixed64(4, math.Float64bits(*v.DoubleValue))
+	}
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket per call site.
+type rateLimiter struct {
+	limit   float64
+	per     time.Duration
+	mu      sync.Mutex
+	buckets map[uintptr]*bucket
+}
+
+type bucket struct {
+	tokens     float64
+	last       time.Time
+	suppressed int
+}
+
+// EnableRateLimit lets every call site write at most limit entries per
+// period, e.g. EnableRateLimit(5, time.Second). Suppressed entries are
+// counted and reported in a "suppressed" field on the next entry written
+// from the same call site. Periods are measured by the Time of the entries.
+func (l *Logger) EnableRateLimit(limit int, per time.Duration) {
+	l.limiter.Store(&rateLimiter{
+		limit:   float64(limit),
+		per:     per,
+		buckets: make(map[uintptr]*bucket),
+	})
+}
+
+func (r *rateLimiter) allow(e *Entry) bool {
+	if e.PC == 0 {
+		return true
+	}
+	now := e.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.buckets[e.PC]
+	if !ok {
+		b = &bucket{tokens: r.limit, last: now}
+		r.buckets[e.PC] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(r.limit, b.tokens+float64(elapsed)/float64(r.per)*r.limit)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		b.suppressed++
+		return false
+	}
+	b.tokens--
+	if b.suppressed > 0 {
+		e.Fields = append(e.Fields, Int("suppressed", b.suppressed))
+		b.suppressed = 0
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactMode selects how redacted text is replaced.
+type RedactMode int
+
+const (
+	// RedactMask replaces secrets with RedactOptions.Mask
+	RedactMask RedactMode = iota
+	// RedactHash replaces secrets with a short hash, so equal values can
+	// still be correlated
+	RedactHash
+	// RedactDrop removes secrets from messages and drops fields entirely
+	RedactDrop
+)
+
+// RedactRule finds secrets in messages and field values. If Pattern has a
+// capture group only the first group is redacted, e.g. the value of a
+// "password=..." pair.
+type RedactRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Validate filters matches, e.g. with a checksum. nil accepts all.
+	Validate func(match string) bool
+}
+
+// Built-in rules
+var (
+	RedactBearerTokens = RedactRule{Name: "bearer", Pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`)}
+	RedactJWTs         = RedactRule{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)}
+	RedactCreditCards  = RedactRule{Name: "card", Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), Validate: luhnValid}
+	RedactEmails       = RedactRule{Name: "email", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)}
+	RedactPasswords    = RedactRule{Name: "password", Pattern: regexp.MustCompile(`(?i)(?:\b|_)(?:password|passwd|pwd|secret)\s*[=:]\s*("[^"]*"|[^\s&,;]+)`)}
+)
+
+// DefaultRedactRules returns the built-in rules.
+func DefaultRedactRules() []RedactRule {
+	return []RedactRule{RedactBearerTokens, RedactJWTs, RedactCreditCards, RedactEmails, RedactPasswords}
+}
+
+// DefaultRedactFields returns the field keys whose values are always
+// redacted.
+func DefaultRedactFields() []string {
+	return []string{"password", "passwd", "secret", "token", "authorization", "api_key", "apikey"}
+}
+
+// RedactOptions configures EnableRedaction.
+type RedactOptions struct {
+	// Rules are applied to messages and to string, error and fmt.Stringer
+	// field values. nil uses DefaultRedactRules.
+	Rules []RedactRule
+	// Fields lists keys, case insensitive, whose values are redacted
+	// entirely. Keys inside groups match by their own name. nil uses
+	// DefaultRedactFields.
+	Fields []string
+	Mode   RedactMode
+	// Mask replaces secrets in RedactMask mode, defaults to "[REDACTED]".
+	Mask string
+	// HashKey makes RedactHash use HMAC-SHA256 with this key instead of
+	// plain SHA-256.
+	HashKey []byte
+}
+
+// redactor applies RedactOptions to entries.
+type redactor struct {
+	options RedactOptions
+	fields  map[string]struct{}
+}
+
+// EnableRedaction removes secrets and personal data from the message and
+// fields of every entry before the hooks, sinks or outputs see it.
+func (l *Logger) EnableRedaction(options RedactOptions) {
+	if options.Rules == nil {
+		options.Rules = DefaultRedactRules()
+	}
+	if options.Fields == nil {
+		options.Fields = DefaultRedactFields()
+	}
+	if options.Mask == "" {
+		options.Mask = "[REDACTED]"
+	}
+	r := &redactor{options: options, fields: make(map[string]struct{}, len(options.Fields))}
+	for _, key := range options.Fields {
+		r.fields[strings.ToLower(key)] = struct{}{}
+	}
+	l.redactor.Store(r)
+}
+
+func (r *redactor) redact(e *Entry) {
+	e.Message = r.redactString(e.Message)
+	e.Fields = r.redactFields(e.Fields)
+}
+
+// redactFields returns a copy of fields with secrets replaced. Hooks may
+// have set fields the caller still holds, so they are not modified in place.
+func (r *redactor) redactFields(fields []Field) []Field {
+	out := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if _, ok := r.fields[strings.ToLower(f.Key)]; ok {
+			if r.options.Mode == RedactDrop {
+				continue
+			}
+			f.Value = r.replacement(f.String())
+			out = append(out, f)
+			continue
+		}
+		switch v := f.Value.(type) {
+		case []Field:
+			f.Value = r.redactFields(v)
+		case string:
+			f.Value = r.redactString(v)
+		case error, fmt.Stringer:
+			if s := f.String(); s != "" {
+				if redacted := r.redactString(s); redacted != s {
+					f.Value = redacted
+				}
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func (r *redactor) redactString(s string) string {
+	for _, rule := range r.options.Rules {
+		s = r.applyRule(rule, s)
+	}
+	return s
+}
+
+func (r *redactor) applyRule(rule RedactRule, s string) string {
+	matches := rule.Pattern.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		if rule.Validate != nil && !rule.Validate(s[start:end]) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(r.replacement(s[start:end]))
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func (r *redactor) replacement(secret string) string {
+	switch r.options.Mode {
+	case RedactHash:
+		var sum []byte
+		if r.options.HashKey != nil {
+			mac := hmac.New(sha256.New, r.options.HashKey)
+			mac.Write([]byte(secret))
+			sum = mac.Sum(nil)
+		} else {
+			h := sha256.Sum256([]byte(secret))
+			sum = h[:]
+		}
+		return "sha256:" + hex.EncodeToString(sum[:6])
+	case RedactDrop:
+		return ""
+	default:
+		return r.options.Mask
+	}
+}
+
+// luhnValid checks the credit card checksum of a number with optional spaces
+// or dashes.
+func luhnValid(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
@@ -0,0 +1,179 @@
+package logging
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// Sink is an additional output of a Logger. Write is called for every entry
+// that passes the sink's SinkOptions, the level of the Logger or module does
+// not apply to sinks.
+type Sink interface {
+	Write(e *Entry) error
+}
+
+// SinkOptions selects the entries a sink receives.
+type SinkOptions struct {
+	// Level is the minimum level the sink receives. The zero value is DEBUG,
+	// set TRACE to receive every entry.
+	Level LogLevel
+	// Include limits the sink to these modules and their children.
+	// "General" selects entries logged on the Logger itself. Empty receives
+	// every module.
+	Include []string
+	// Exclude lists modules the sink never receives, with their children.
+	Exclude []string
+}
+
+type registeredSink struct {
+	sink    Sink
+	level   LogLevel
+	include map[string]struct{}
+	exclude map[string]struct{}
+}
+
+func (rs *registeredSink) accepts(level LogLevel, module string) bool {
+	if level.Severity() < rs.level.Severity() {
+		return false
+	}
+	if matchModule(rs.exclude, module) {
+		return false
+	}
+	if len(rs.include) > 0 {
+		return matchModule(rs.include, module)
+	}
+	return true
+}
+
+// matchModule reports whether module or one of its parents, e.g. "Database"
+// for "Database.Pool", is in set.
+func matchModule(set map[string]struct{}, module string) bool {
+	if len(set) == 0 {
+		return false
+	}
+	for {
+		if _, ok := set[module]; ok {
+			return true
+		}
+		i := strings.LastIndexByte(module, '.')
+		if i < 0 {
+			return false
+		}
+		module = module[:i]
+	}
+}
+
+// sinkSet is shared by a Logger and the children created from it with With.
+type sinkSet struct {
+	mu    sync.RWMutex
+	sinks []*registeredSink
+}
+
+func toSet(names []string) map[string]struct{} {
+	if len(names) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		set[n] = struct{}{}
+	}
+	return set
+}
+
+// AddSink adds an output that receives the entries selected by options, in
+// addition to the log.Logger or slog.Handler of the Logger. Create the Logger
+// with NewLogger(nil, level) to write to sinks only.
+func (l *Logger) AddSink(sink Sink, options SinkOptions) {
+	rs := &registeredSink{
+		sink:    sink,
+		level:   options.Level,
+		include: toSet(options.Include),
+		exclude: toSet(options.Exclude),
+	}
+	l.sinks.mu.Lock()
+	defer l.sinks.mu.Unlock()
+	// Copy on write, so writers can range over the slice without the lock
+	sinks := make([]*registeredSink, 0, len(l.sinks.sinks)+1)
+	sinks = append(sinks, l.sinks.sinks...)
+	l.sinks.sinks = append(sinks, rs)
+}
+
+// RemoveSink removes a sink added with AddSink.
+func (l *Logger) RemoveSink(sink Sink) {
+	l.sinks.mu.Lock()
+	defer l.sinks.mu.Unlock()
+	sinks := make([]*registeredSink, 0, len(l.sinks.sinks))
+	for _, rs := range l.sinks.sinks {
+		if rs.sink != sink {
+			sinks = append(sinks, rs)
+		}
+	}
+	l.sinks.sinks = sinks
+}
+
+func (set *sinkSet) list() []*registeredSink {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.sinks
+}
+
+// accepts reports whether any sink would receive an entry.
+func (set *sinkSet) accepts(level LogLevel, module string) bool {
+	for _, rs := range set.list() {
+		if rs.accepts(level, module) {
+			return true
+		}
+	}
+	return false
+}
+
+// write hands the entry to every sink that accepts it.
+func (set *sinkSet) write(e *Entry) {
+	module := e.ModuleName()
+	for _, rs := range set.list() {
+		if rs.accepts(e.Level, module) {
+			_ = rs.sink.Write(e)
+		}
+	}
+}
+
+// WriterSink encodes entries and writes them, one per line, to an
+// io.Writer.
+type WriterSink struct {
+	mu      sync.Mutex
+	w       io.Writer
+	encoder Encoder
+}
+
+// NewWriterSink returns a sink writing to w. A nil encoder writes text with a
+// timestamp, colored as in ColorAuto mode.
+func NewWriterSink(w io.Writer, encoder Encoder) *WriterSink {
+	if encoder == nil {
+		encoder = TextEncoder{DisableTextModifier: !defaultColored(w), TimeFormat: "2006/01/02 15:04:05", ColorDepth: DetectColorDepth()}
+	}
+	return &WriterSink{w: w, encoder: encoder}
+}
+
+func (s *WriterSink) Write(e *Entry) error {
+	line, err := s.encoder.Encode(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(line)
+	return err
+}
+
+// Sync commits the written lines to storage if the writer supports it, like
+// *os.File.
+func (s *WriterSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if syncer, ok := s.w.(syncer); ok {
+		return syncer.Sync()
+	}
+	return nil
+}
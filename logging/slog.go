@@ -0,0 +1,224 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Slog levels of the levels slog does not define.
+const (
+	LevelTrace  = slog.LevelDebug - 4
+	LevelNotice = slog.LevelInfo + 2
+	LevelFail   = slog.LevelError + 4
+)
+
+// SlogLevel maps a LogLevel to the matching slog level.
+func SlogLevel(level LogLevel) slog.Level {
+	switch {
+	case level == TRACE:
+		return LevelTrace
+	case level == DEBUG:
+		return slog.LevelDebug
+	case level == INFO:
+		return slog.LevelInfo
+	case level == NOTICE:
+		return LevelNotice
+	case level == WARN:
+		return slog.LevelWarn
+	case level == ERROR:
+		return slog.LevelError
+	case level == FAIL:
+		return LevelFail
+	default:
+		return LevelFail + 4
+	}
+}
+
+// FromSlogLevel maps a slog level to a LogLevel. Levels between two slog
+// levels round down, so custom slog levels land on the nearest lower level.
+func FromSlogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return TRACE
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < LevelNotice:
+		return INFO
+	case level < slog.LevelWarn:
+		return NOTICE
+	case level < slog.LevelError:
+		return WARN
+	case level < LevelFail:
+		return ERROR
+	default:
+		return FAIL
+	}
+}
+
+// NewSlogLogger returns a Logger that writes its entries through handler
+// instead of a log.Logger. Module names are passed as the attribute "module".
+func NewSlogLogger(handler slog.Handler, level LogLevel) *Logger {
+	l := NewLogger(nil, level)
+	l.handler = handler
+	return l
+}
+
+// writeSlog hands an entry to a slog.Handler.
+func writeSlog(handler slog.Handler, e *Entry) {
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	level := SlogLevel(e.Level)
+	if !handler.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(e.Time, level, e.Message, e.PC)
+	if e.Module != "" {
+		r.AddAttrs(slog.String("module", e.Module))
+	}
+	r.AddAttrs(fieldsToAttrs(e.Fields)...)
+	_ = handler.Handle(ctx, r)
+}
+
+// Handler returns a slog.Handler that logs through l, so a *slog.Logger
+// created from it ends up in the Logger's output.
+func (l *Logger) Handler() slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+// Handler returns a slog.Handler that logs through the module logger.
+func (sm *SystemModuleLogger) Handler() slog.Handler {
+	return &slogHandler{logger: sm.logger, module: sm}
+}
+
+// slogHandler adapts a Logger or SystemModuleLogger to slog.Handler.
+type slogHandler struct {
+	logger *Logger
+	module *SystemModuleLogger
+	fields []Field     // attributes added outside of any group
+	groups []slogGroup // groups opened with WithGroup, outermost first
+}
+
+type slogGroup struct {
+	name   string
+	fields []Field
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.enabled(FromSlogLevel(level), h.module)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var fields []Field
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+	// Close the open groups from the innermost outwards. Groups without
+	// attributes are dropped.
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		fields = joinFields(h.groups[i].fields, fields)
+		if len(fields) > 0 {
+			fields = []Field{Group(h.groups[i].name, fields...)}
+		}
+	}
+
+	e := h.logger.newEntry(FromSlogLevel(r.Level), h.module, r.Message, joinFields(FieldsFromContext(ctx), h.fields, fields))
+	e.Time = r.Time
+	e.ctx = ctx
+	if pc, stack := h.logger.needsCallers(e.Level); pc && r.PC != 0 {
+		pcs := []uintptr{r.PC}
+		if stack {
+			// Cut the frames of slog and Handle off the stack
+			all := callers(0, true)
+			for i := range all {
+				if all[i] == r.PC {
+					pcs = all[i:]
+					break
+				}
+			}
+		}
+		h.logger.annotate(e, pcs, stack)
+	}
+	h.logger.process(e, h.module)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []Field
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	if len(fields) == 0 {
+		return h
+	}
+	child := *h
+	if len(h.groups) == 0 {
+		child.fields = joinFields(h.fields, fields)
+	} else {
+		child.groups = append([]slogGroup(nil), h.groups...)
+		last := &child.groups[len(child.groups)-1]
+		last.fields = joinFields(last.fields, fields)
+	}
+	return &child
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.groups = append(append([]slogGroup(nil), h.groups...), slogGroup{name: name})
+	return &child
+}
+
+// appendAttr converts a resolved slog attribute to a field. Empty attributes
+// and empty groups are dropped, groups with an empty key are inlined.
+func appendAttr(fields []Field, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		var group []Field
+		for _, ga := range a.Value.Group() {
+			group = appendAttr(group, ga)
+		}
+		if len(group) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			return append(fields, group...)
+		}
+		return append(fields, Group(a.Key, group...))
+	case slog.KindString:
+		return append(fields, String(a.Key, a.Value.String()))
+	case slog.KindInt64:
+		return append(fields, Int64(a.Key, a.Value.Int64()))
+	case slog.KindBool:
+		return append(fields, Bool(a.Key, a.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, Duration(a.Key, a.Value.Duration()))
+	default:
+		return append(fields, Any(a.Key, a.Value.Any()))
+	}
+}
+
+// fieldsToAttrs converts fields to slog attributes, keeping groups nested.
+func fieldsToAttrs(fields []Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, f := range fields {
+		switch v := f.Value.(type) {
+		case []Field:
+			attrs = append(attrs, slog.Attr{Key: f.Key, Value: slog.GroupValue(fieldsToAttrs(v)...)})
+		case time.Duration:
+			attrs = append(attrs, slog.Duration(f.Key, v))
+		default:
+			attrs = append(attrs, slog.Any(f.Key, v))
+		}
+	}
+	return attrs
+}
@@ -1,51 +1,156 @@
 package logging
 
+// The package functions call the internal helpers of std directly, so they
+// sit at the same call depth as the Logger methods.
+
+// Trace level log with gray color
+func Trace(msg ...string) {
+	if std.enabled(TRACE, nil) {
+		std.logWithLevel(TRACE, nil, msg...)
+	}
+}
+
 // Debug level log with blue color
 func Debug(msg ...string) {
-	std.Debug(msg...)
+	if std.enabled(DEBUG, nil) {
+		std.logWithLevel(DEBUG, nil, msg...)
+	}
 }
 
 // Info level log with green color
 func Info(msg ...string) {
-	std.Info(msg...)
+	if std.enabled(INFO, nil) {
+		std.logWithLevel(INFO, nil, msg...)
+	}
+}
+
+// Notice level log with cyan color
+func Notice(msg ...string) {
+	if std.enabled(NOTICE, nil) {
+		std.logWithLevel(NOTICE, nil, msg...)
+	}
 }
 
 // Warn level log with yellow color
 func Warn(msg ...string) {
-	std.Warn(msg...)
+	if std.enabled(WARN, nil) {
+		std.logWithLevel(WARN, nil, msg...)
+	}
 }
 
 // Error level log with red color
 func Error(msg ...string) {
-	std.Error(msg...)
+	if std.enabled(ERROR, nil) {
+		std.logWithLevel(ERROR, nil, msg...)
+	}
 }
 
 // Error level log with red color
 func Fail(msg ...string) {
-	std.Fail(msg...)
+	if std.enabled(FAIL, nil) {
+		std.logWithLevel(FAIL, nil, msg...)
+	}
+}
+
+// Trace level log with gray color
+func TraceF(format string, v ...any) {
+	if std.enabled(TRACE, nil) {
+		std.logWithLevelF(TRACE, nil, format, v...)
+	}
 }
 
 // Debug level log with blue color
 func DebugF(format string, v ...any) {
-	std.DebugF(format, v...)
+	if std.enabled(DEBUG, nil) {
+		std.logWithLevelF(DEBUG, nil, format, v...)
+	}
 }
 
 // Info level log with green color
 func InfoF(format string, v ...any) {
-	std.InfoF(format, v...)
+	if std.enabled(INFO, nil) {
+		std.logWithLevelF(INFO, nil, format, v...)
+	}
+}
+
+// Notice level log with cyan color
+func NoticeF(format string, v ...any) {
+	if std.enabled(NOTICE, nil) {
+		std.logWithLevelF(NOTICE, nil, format, v...)
+	}
 }
 
 // Warn level log with yellow color
 func WarnF(format string, v ...any) {
-	std.WarnF(format, v...)
+	if std.enabled(WARN, nil) {
+		std.logWithLevelF(WARN, nil, format, v...)
+	}
 }
 
 // Error level log with red color
 func ErrorF(format string, v ...any) {
-	std.ErrorF(format, v...)
+	if std.enabled(ERROR, nil) {
+		std.logWithLevelF(ERROR, nil, format, v...)
+	}
 }
 
 // Error level log with red color
 func FailF(format string, v ...any) {
-	std.FailF(format, v...)
+	if std.enabled(FAIL, nil) {
+		std.logWithLevelF(FAIL, nil, format, v...)
+	}
+}
+
+// With returns a child of the default logger that adds fields to every entry
+func With(fields ...Field) *Logger {
+	return std.With(fields...)
+}
+
+// Trace level log with fields
+func TraceW(msg string, fields ...Field) {
+	if std.enabled(TRACE, nil) {
+		std.logWithLevelW(TRACE, nil, msg, fields...)
+	}
+}
+
+// Debug level log with fields
+func DebugW(msg string, fields ...Field) {
+	if std.enabled(DEBUG, nil) {
+		std.logWithLevelW(DEBUG, nil, msg, fields...)
+	}
+}
+
+// Info level log with fields
+func InfoW(msg string, fields ...Field) {
+	if std.enabled(INFO, nil) {
+		std.logWithLevelW(INFO, nil, msg, fields...)
+	}
+}
+
+// Notice level log with fields
+func NoticeW(msg string, fields ...Field) {
+	if std.enabled(NOTICE, nil) {
+		std.logWithLevelW(NOTICE, nil, msg, fields...)
+	}
+}
+
+// Warn level log with fields
+func WarnW(msg string, fields ...Field) {
+	if std.enabled(WARN, nil) {
+		std.logWithLevelW(WARN, nil, msg, fields...)
+	}
+}
+
+// Error level log with fields
+func ErrorW(msg string, fields ...Field) {
+	if std.enabled(ERROR, nil) {
+		std.logWithLevelW(ERROR, nil, msg, fields...)
+	}
+}
+
+// Fail level log with fields
+func FailW(msg string, fields ...Field) {
+	if std.enabled(FAIL, nil) {
+		std.logWithLevelW(FAIL, nil, msg, fields...)
+	}
 }
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"log"
+)
+
+// logsFile reports whether entries are written to a log.Logger that prints
+// the file of the call site.
+func (l *Logger) logsFile() bool {
+	return l.handler == nil && l.logger != nil && l.logger.Flags()&(log.Lshortfile|log.Llongfile) != 0
+}
+
+// writeStdlog writes a line through Output, so the log.Logger's own lock is
+// held and the line does not interleave with other writers of the same
+// log.Logger.
+func writeStdlog(logger *log.Logger, e *Entry, line []byte) {
+	depth := 1
+	if logger.Flags()&(log.Lshortfile|log.Llongfile) != 0 {
+		depth = callDepth(e.PC)
+	}
+	_ = logger.Output(depth, string(line))
+}
+
+// callDepth returns the calldepth of Output that reports the frame of pc,
+// counted from the caller of callDepth. When pc is not on the stack, e.g.
+// for entries written by the async worker, the depth lies beyond the stack
+// and the log package prints "???".
+func callDepth(pc uintptr) int {
+	// skip callDepth, the first frame is writeStdlog, which is calldepth 1
+	pcs := callers(1, true)
+	if pc != 0 {
+		for i, p := range pcs {
+			if p == pc {
+				return i + 1
+			}
+		}
+	}
+	return len(pcs) + 1
+}
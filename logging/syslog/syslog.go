@@ -0,0 +1,342 @@
+// Package syslog provides a logging.Sink that sends entries to a syslog
+// server or the local syslog daemon in RFC 5424 or RFC 3164 format.
+package syslog
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/internal/netconn"
+)
+
+// Format is the message format.
+type Format int
+
+const (
+	// RFC5424 is the structured syslog format
+	RFC5424 Format = iota
+	// RFC3164 is the legacy BSD format, fields are appended as key=value
+	RFC3164
+)
+
+// Framing separates messages on stream connections.
+type Framing int
+
+const (
+	// FramingAuto uses octet counting over TCP and TLS and newlines over
+	// unix stream sockets
+	FramingAuto Framing = iota
+	// OctetCounting prefixes every message with its length (RFC 6587)
+	OctetCounting
+	// NonTransparent terminates every message with a newline. Newlines in
+	// the message are escaped as #012, the way rsyslog shows them.
+	NonTransparent
+)
+
+// Facility is the syslog facility.
+type Facility int
+
+const (
+	Kern Facility = iota
+	User
+	Mail
+	Daemon
+	Auth
+	Syslog
+	Lpr
+	News
+	Uucp
+	Cron
+	Authpriv
+	Ftp
+	Local0 Facility = iota + 4
+	Local1
+	Local2
+	Local3
+	Local4
+	Local5
+	Local6
+	Local7
+)
+
+// Severity is the syslog severity.
+type Severity int
+
+const (
+	Emergency Severity = iota
+	Alert
+	Critical
+	Err
+	Warning
+	Notice
+	Informational
+	Debug
+)
+
+// SeverityOf maps a log level to a syslog severity.
+func SeverityOf(level logging.LogLevel) Severity {
+	switch level {
+	case logging.TRACE, logging.DEBUG:
+		return Debug
+	case logging.NOTICE:
+		return Notice
+	case logging.WARN:
+		return Warning
+	case logging.ERROR:
+		return Err
+	case logging.FAIL:
+		return Critical
+	default:
+		return Informational
+	}
+}
+
+// Options configures a Sink.
+type Options struct {
+	// Network is "udp", "tcp", "tls", "unix" or "unixgram". Empty connects
+	// to the local syslog daemon.
+	Network string
+	// Address of the server, or the socket path for unix networks
+	Address string
+	// TLSConfig is used for the "tls" network
+	TLSConfig *tls.Config
+	Format    Format
+	Framing   Framing
+	// Facility defaults to User, programs cannot log as Kern
+	Facility Facility
+	// Hostname defaults to os.Hostname
+	Hostname string
+	// AppName defaults to the program name
+	AppName string
+	// ModuleAsAppName sends the module name as APP-NAME (or TAG in RFC 3164)
+	// instead of as MSGID. Entries of the Logger itself keep AppName.
+	ModuleAsAppName bool
+	// SDID is the STRUCTURED-DATA ID fields are sent under, defaults to
+	// "fields@32473"
+	SDID string
+	// Timeout for connecting and writing, defaults to 5 seconds
+	Timeout time.Duration
+}
+
+// Sink writes entries to syslog. It reconnects when the connection is lost.
+type Sink struct {
+	options Options
+	pid     string
+
+	mu      sync.Mutex
+	conn    netconn.Conn
+	network string // network of conn, set for the local daemon by dial
+}
+
+// localSockets are tried in order for the local syslog daemon.
+var localSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// New returns a sink and opens the connection.
+func New(options Options) (*Sink, error) {
+	if options.Facility == Kern {
+		options.Facility = User
+	}
+	if options.Hostname == "" {
+		options.Hostname, _ = os.Hostname()
+	}
+	if options.AppName == "" {
+		options.AppName = filepath.Base(os.Args[0])
+	}
+	if options.SDID == "" {
+		options.SDID = "fields@32473"
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 5 * time.Second
+	}
+	s := &Sink{options: options, pid: strconv.Itoa(os.Getpid())}
+	s.conn.Dial = s.dial
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.conn.Connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// dial connects to the configured address. Callers hold s.mu.
+func (s *Sink) dial() (net.Conn, error) {
+	o := s.options
+	var conn net.Conn
+	var err error
+	switch o.Network {
+	case "":
+		for _, network := range []string{"unixgram", "unix"} {
+			for _, path := range localSockets {
+				if conn, err = net.DialTimeout(network, path, o.Timeout); err == nil {
+					s.network = network
+					return conn, nil
+				}
+			}
+		}
+		return nil, errors.New("syslog: no local syslog socket found")
+	case "tls":
+		dialer := &net.Dialer{Timeout: o.Timeout}
+		conn, err = tls.DialWithDialer(dialer, "tcp", o.Address, o.TLSConfig)
+	default:
+		conn, err = net.DialTimeout(o.Network, o.Address, o.Timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.network = o.Network
+	return conn, nil
+}
+
+// Write formats the entry and sends it. A failed write is retried once on a
+// new connection.
+func (s *Sink) Write(e *logging.Entry) error {
+	msg := s.format(e)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.Send(func(conn net.Conn) error { return s.send(conn, msg) })
+}
+
+// send frames and writes one message. Callers hold s.mu.
+func (s *Sink) send(conn net.Conn, msg []byte) error {
+	switch s.framing() {
+	case OctetCounting:
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	case NonTransparent:
+		msg = append(bytes.ReplaceAll(msg, []byte("\n"), []byte("#012")), '\n')
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(s.options.Timeout))
+	_, err := conn.Write(msg)
+	return err
+}
+
+func (s *Sink) framing() Framing {
+	switch s.network {
+	case "udp", "udp4", "udp6", "unixgram":
+		return FramingAuto // datagrams are not framed
+	}
+	if s.options.Framing != FramingAuto {
+		return s.options.Framing
+	}
+	if s.network == "unix" {
+		return NonTransparent
+	}
+	return OctetCounting
+}
+
+// Close closes the connection.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.Close()
+}
+
+func (s *Sink) format(e *logging.Entry) []byte {
+	o := s.options
+	pri := int(o.Facility)*8 + int(SeverityOf(e.Level))
+	appName := o.AppName
+	msgID := "-"
+	if e.Module != "" {
+		if o.ModuleAsAppName {
+			appName = e.Module
+		} else {
+			msgID = header(e.Module, 32)
+		}
+	}
+	t := e.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	fields := logging.FlattenFields(e.Fields)
+
+	var b strings.Builder
+	if o.Format == RFC3164 {
+		fmt.Fprintf(&b, "<%d>%s %s %s[%s]: %s", pri, t.Format(time.Stamp), header(o.Hostname, 255), header(appName, 32), s.pid, e.Message)
+		if e.Module != "" && !o.ModuleAsAppName {
+			fields = append([]logging.Field{logging.String("module", e.Module)}, fields...)
+		}
+		for _, f := range fields {
+			b.WriteByte(' ')
+			b.WriteString(f.Key)
+			b.WriteByte('=')
+			b.WriteString(quote(f.String()))
+		}
+		return []byte(b.String())
+	}
+
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s %s ", pri, t.Format("2006-01-02T15:04:05.000000Z07:00"),
+		header(o.Hostname, 255), header(appName, 48), s.pid, msgID)
+	if len(fields) == 0 {
+		b.WriteByte('-')
+	} else {
+		b.WriteByte('[')
+		b.WriteString(sdName(o.SDID))
+		for _, f := range fields {
+			b.WriteByte(' ')
+			b.WriteString(sdName(f.Key))
+			b.WriteString(`="`)
+			b.WriteString(sdEscape(f.String()))
+			b.WriteByte('"')
+		}
+		b.WriteByte(']')
+	}
+	if e.Message != "" {
+		b.WriteByte(' ')
+		b.WriteString(e.Message)
+	}
+	return []byte(b.String())
+}
+
+// header returns s as printable US-ASCII without spaces, at most max bytes
+// long, or the NILVALUE "-".
+func header(s string, max int) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < max; i++ {
+		c := s[i]
+		if c > ' ' && c < 0x7f {
+			b = append(b, c)
+		} else {
+			b = append(b, '_')
+		}
+	}
+	if len(b) == 0 {
+		return "-"
+	}
+	return string(b)
+}
+
+// sdName returns a valid SD-NAME, which may not contain '=', ']', '"' or
+// spaces.
+func sdName(s string) string {
+	name := []byte(header(s, 32))
+	for i, c := range name {
+		if c == '=' || c == ']' || c == '"' {
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
+
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// sdEscape escapes '"', '\' and ']' in a PARAM-VALUE.
+func sdEscape(s string) string {
+	return sdEscaper.Replace(s)
+}
+
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
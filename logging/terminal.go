@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ColorMode selects when the default text output is colored.
+type ColorMode int
+
+const (
+	// ColorAuto colors output written to a terminal and honors the NO_COLOR,
+	// FORCE_COLOR and TERM environment variables
+	ColorAuto ColorMode = iota
+	// ColorAlways always colors the output
+	ColorAlways
+	// ColorNever never colors the output
+	ColorNever
+)
+
+var colorModeNames = [...]string{"auto", "always", "never"}
+
+func (mode ColorMode) String() string {
+	if mode >= 0 && int(mode) < len(colorModeNames) {
+		return colorModeNames[mode]
+	}
+	return fmt.Sprintf("ColorMode(%d)", int(mode))
+}
+
+// Set implements flag.Value and accepts "auto", "always" and "never".
+func (mode *ColorMode) Set(s string) error {
+	for i, name := range colorModeNames {
+		if strings.EqualFold(s, name) {
+			*mode = ColorMode(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("logging: unknown color mode %q", s)
+}
+
+// SetColorMode sets when the default text output is colored. Setting
+// DisableTextModifier turns colors off regardless of the mode. The
+// environment is read again on the next entry.
+func (l *Logger) SetColorMode(mode ColorMode) {
+	l.colorMode = mode
+	l.terminal.reset()
+}
+
+// IsTerminal reports whether w is a file connected to a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f.Fd())
+}
+
+// colorsFromEnv applies FORCE_COLOR, NO_COLOR and TERM=dumb, in that order.
+// ok is false when none of them decides.
+func colorsFromEnv() (colored bool, ok bool) {
+	if force, set := os.LookupEnv("FORCE_COLOR"); set {
+		switch force {
+		case "0", "false":
+			return false, true
+		default:
+			return true, true
+		}
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false, true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false, true
+	}
+	return false, false
+}
+
+// terminalCache remembers the color settings of the environment and whether
+// the last file a Logger wrote to is a terminal, so neither the environment
+// nor the ioctl is read for every entry. SetColorMode and SetLogger reset it.
+type terminalCache struct {
+	mu       sync.Mutex
+	resolved bool
+	envColor bool // decision of colorsFromEnv, if envSet
+	envSet   bool
+	depth    ColorDepth
+	file     *os.File
+	terminal bool
+}
+
+// env returns the cached results of colorsFromEnv and DetectColorDepth.
+// Callers hold c.mu.
+func (c *terminalCache) env() {
+	if !c.resolved {
+		c.envColor, c.envSet = colorsFromEnv()
+		c.depth = DetectColorDepth()
+		c.resolved = true
+	}
+}
+
+func (c *terminalCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resolved = false
+	c.file = nil
+}
+
+// colored is the ColorAuto decision for w.
+func (c *terminalCache) colored(w io.Writer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.env()
+	if c.envSet {
+		return c.envColor
+	}
+	f, ok := w.(*os.File)
+	if !ok || !canDetectTerminal {
+		return true
+	}
+	if c.file != f {
+		c.file = f
+		c.terminal = isTerminal(f.Fd())
+	}
+	return c.terminal
+}
+
+func (c *terminalCache) colorDepth() ColorDepth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.env()
+	return c.depth
+}
+
+// colored decides whether the default text output to w is colored. In auto
+// mode writers that cannot be checked, e.g. buffers, keep the colors.
+func (l *Logger) colored(w io.Writer) bool {
+	if l.DisableTextModifier {
+		return false
+	}
+	switch l.colorMode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	return l.terminal.colored(w)
+}
+
+// defaultColored is the ColorAuto decision for writers checked only once.
+func defaultColored(w io.Writer) bool {
+	if colored, ok := colorsFromEnv(); ok {
+		return colored
+	}
+	if f, ok := w.(*os.File); ok && canDetectTerminal {
+		return isTerminal(f.Fd())
+	}
+	return true
+}
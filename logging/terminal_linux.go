@@ -0,0 +1,18 @@
+//go:build linux
+
+package logging
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const canDetectTerminal = true
+
+// isTerminal asks the kernel for the terminal attributes of fd, which only
+// succeeds for terminals.
+func isTerminal(fd uintptr) bool {
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
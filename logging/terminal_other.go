@@ -0,0 +1,11 @@
+//go:build !linux
+
+package logging
+
+// canDetectTerminal is false where isTerminal is not implemented; ColorAuto
+// then relies on the environment variables only.
+const canDetectTerminal = false
+
+func isTerminal(fd uintptr) bool {
+	return false
+}
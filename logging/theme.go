@@ -0,0 +1,98 @@
+package logging
+
+// LevelStyle is the style of the entries of one level.
+type LevelStyle struct {
+	// Tag styles the [LEVEL] tag
+	Tag TextModifier
+	// Text styles the message. Empty uses the TextColor of the module.
+	Text TextModifier
+}
+
+// Theme maps the parts of a text line to styles. Parts without a style are
+// printed uncolored.
+type Theme struct {
+	Levels map[LogLevel]LevelStyle
+	// Module styles module names without a NameColor and "General"
+	Module TextModifier
+	// Time styles the timestamp of a TextEncoder with a TimeFormat
+	Time TextModifier
+	// FieldKey styles the keys of fields
+	FieldKey TextModifier
+}
+
+// DefaultTheme is the theme used when none is set.
+func DefaultTheme() *Theme {
+	return &Theme{
+		Levels: map[LogLevel]LevelStyle{
+			TRACE:  {Tag: BrightBlack},
+			DEBUG:  {Tag: Blue},
+			INFO:   {Tag: Green},
+			NOTICE: {Tag: Cyan},
+			WARN:   {Tag: Yellow},
+			ERROR:  {Tag: Red},
+			FAIL:   {Tag: Red + MagentaBG, Text: Red + MagentaBG},
+		},
+		FieldKey: Cyan,
+	}
+}
+
+// HighContrastTheme uses bold, bright colors.
+func HighContrastTheme() *Theme {
+	return &Theme{
+		Levels: map[LogLevel]LevelStyle{
+			TRACE:  {Tag: BrightWhite},
+			DEBUG:  {Tag: Bold + BrightCyan},
+			INFO:   {Tag: Bold + BrightGreen},
+			NOTICE: {Tag: Bold + BrightBlue},
+			WARN:   {Tag: Bold + Black + BrightYellowBG},
+			ERROR:  {Tag: Bold + BrightWhite + RedBG, Text: Bold + BrightRed},
+			FAIL:   {Tag: Bold + BrightWhite + MagentaBG, Text: Bold + BrightWhite + RedBG},
+		},
+		Module:   Bold + BrightWhite,
+		Time:     BrightWhite,
+		FieldKey: Bold + BrightCyan,
+	}
+}
+
+// LightTheme avoids yellow and bright colors, which are hard to read on a
+// light background.
+func LightTheme() *Theme {
+	return &Theme{
+		Levels: map[LogLevel]LevelStyle{
+			TRACE:  {Tag: Color256(244)},
+			DEBUG:  {Tag: Blue},
+			INFO:   {Tag: Green},
+			NOTICE: {Tag: Cyan},
+			WARN:   {Tag: Color256(130)},
+			ERROR:  {Tag: Red},
+			FAIL:   {Tag: Bold + White + RedBG, Text: Red},
+		},
+		Time:     Dim,
+		FieldKey: Magenta,
+	}
+}
+
+// MonochromeTheme only uses text attributes, no colors.
+func MonochromeTheme() *Theme {
+	return &Theme{
+		Levels: map[LogLevel]LevelStyle{
+			TRACE:  {Tag: Dim, Text: Dim},
+			DEBUG:  {Tag: Dim},
+			NOTICE: {Tag: Underline},
+			WARN:   {Tag: Bold},
+			ERROR:  {Tag: Bold + Underline},
+			FAIL:   {Tag: Reverse, Text: Bold},
+		},
+		Time:     Dim,
+		FieldKey: Italic,
+	}
+}
+
+// defaultTheme is shared by encoders without a Theme and never modified.
+var defaultTheme = DefaultTheme()
+
+// SetTheme sets the theme of the default text output. nil restores
+// DefaultTheme.
+func (l *Logger) SetTheme(theme *Theme) {
+	l.theme = theme
+}
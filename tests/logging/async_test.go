@@ -0,0 +1,242 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+// blockingSink records entries but waits for release before each write.
+type blockingSink struct {
+	recordingSink
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(e *logging.Entry) error {
+	<-s.release
+	return s.recordingSink.Write(e)
+}
+
+func TestAsyncFlushWritesEverything(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableAsync(logging.AsyncOptions{Size: 8})
+
+	for i := 0; i < 100; i++ {
+		logger.InfoF("entry %d", i)
+	}
+	logger.Flush()
+
+	got := sink.messages()
+	if len(got) != 100 {
+		t.Fatalf("Expected 100 entries, got %d", len(got))
+	}
+	for i, msg := range got {
+		if msg != fmt.Sprintf("entry %d", i) {
+			t.Fatalf("Entries out of order at %d: %q", i, msg)
+		}
+	}
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAsyncDoesNotBlockCaller(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &blockingSink{release: make(chan struct{})}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableAsync(logging.AsyncOptions{Size: 4, Overflow: logging.DropNewest, ReportInterval: -1})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 20; i++ {
+			logger.InfoF("entry %d", i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Logging blocked on a slow sink")
+	}
+	close(sink.release)
+	_ = logger.Close(context.Background())
+
+	// One entry may be in flight, the queue holds 4, the rest is dropped
+	if logger.Dropped() < 15 || logger.Dropped() > 16 {
+		t.Errorf("Expected 15 or 16 dropped entries, got %d", logger.Dropped())
+	}
+	if got := sink.messages(); got[0] != "entry 0" {
+		t.Errorf("DropNewest should keep the first entries, got %v", got)
+	}
+}
+
+func TestAsyncDropOldest(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &blockingSink{release: make(chan struct{})}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableAsync(logging.AsyncOptions{Size: 2, Overflow: logging.DropOldest, ReportInterval: -1})
+
+	for i := 0; i < 10; i++ {
+		logger.InfoF("entry %d", i)
+	}
+	close(sink.release)
+	_ = logger.Close(context.Background())
+
+	got := sink.messages()
+	if got[len(got)-1] != "entry 9" || got[len(got)-2] != "entry 8" {
+		t.Errorf("DropOldest should keep the newest entries, got %v", got)
+	}
+}
+
+func TestAsyncDropBelowLevel(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &blockingSink{release: make(chan struct{})}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableAsync(logging.AsyncOptions{Size: 2, Overflow: logging.DropBelowLevel, DropLevel: logging.WARN, ReportInterval: -1})
+
+	for i := 0; i < 10; i++ {
+		logger.DebugF("debug %d", i)
+	}
+	errorsLogged := make(chan struct{})
+	go func() {
+		logger.Error("important 1")
+		logger.Error("important 2")
+		close(errorsLogged)
+	}()
+	close(sink.release)
+	<-errorsLogged
+	_ = logger.Close(context.Background())
+
+	got := sink.messages()
+	if got[len(got)-2] != "important 1" || got[len(got)-1] != "important 2" {
+		t.Errorf("Entries at DropLevel must never be dropped, got %v", got)
+	}
+	if logger.Dropped() == 0 {
+		t.Error("Expected dropped debug entries")
+	}
+}
+
+func TestAsyncReportsDroppedEntries(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &blockingSink{release: make(chan struct{})}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableAsync(logging.AsyncOptions{Size: 1, Overflow: logging.DropNewest, ReportInterval: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		logger.Info("flood")
+	}
+	close(sink.release)
+	_ = logger.Close(context.Background())
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	last := sink.entries[len(sink.entries)-1]
+	if last.Level != logging.WARN || len(last.Fields) != 1 || last.Fields[0].Key != "dropped" {
+		t.Fatalf("Expected a dropped-entries warning on Close, got %+v", last)
+	}
+	if last.Fields[0].Value != logger.Dropped() {
+		t.Errorf("Reported %v dropped entries, counter says %d", last.Fields[0].Value, logger.Dropped())
+	}
+}
+
+func TestAsyncCloseHonorsContext(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &blockingSink{release: make(chan struct{})}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableAsync(logging.AsyncOptions{Size: 4, ReportInterval: -1})
+	logger.Info("stuck")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := logger.Close(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+	close(sink.release)
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// After Close entries are written synchronously
+	logger.Info("sync")
+	if got := sink.messages(); got[len(got)-1] != "sync" {
+		t.Errorf("Expected synchronous write after Close, got %v", got)
+	}
+}
+
+func TestAsyncEnableTwiceDrainsOldQueue(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableAsync(logging.AsyncOptions{Size: 8})
+	for i := 0; i < 5; i++ {
+		logger.InfoF("entry %d", i)
+	}
+	logger.EnableAsync(logging.AsyncOptions{Size: 8, ReportInterval: -1})
+	if got := sink.messages(); len(got) != 5 {
+		t.Fatalf("Expected the old queue to be drained, got %v", got)
+	}
+	for i := 5; i < 10; i++ {
+		logger.InfoF("entry %d", i)
+	}
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got := sink.messages()
+	for i, msg := range got {
+		if msg != fmt.Sprintf("entry %d", i) {
+			t.Fatalf("Entries out of order at %d: %v", i, got)
+		}
+	}
+	if len(got) != 10 {
+		t.Errorf("Expected 10 entries, got %v", got)
+	}
+}
+
+func TestAsyncStdlogHeader(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", log.Lshortfile), logging.DEBUG)
+	logger.DisableTextModifier = true
+	logger.EnableAsync(logging.AsyncOptions{ReportInterval: -1})
+
+	logger.Info("queued")
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The call site is not on the stack of the worker
+	if got := "???:0: [INFO]\t[General]\tqueued\n"; buf.String() != got {
+		t.Errorf("Expected %q, got %q", got, buf.String())
+	}
+}
+
+func TestEnableFeaturesWhileLogging(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			logger.InfoF("entry %d", i)
+		}
+	}()
+	logger.EnableAsync(logging.AsyncOptions{ReportInterval: -1})
+	logger.EnableDedup(time.Minute)
+	logger.EnableRateLimit(1000, time.Second)
+	logger.EnableRedaction(logging.RedactOptions{})
+	logger.EnableAsync(logging.AsyncOptions{ReportInterval: -1})
+	<-done
+	if err := logger.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(sink.messages()); n != 200 {
+		t.Errorf("Expected 200 entries, got %d", n)
+	}
+}
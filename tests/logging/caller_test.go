@@ -0,0 +1,165 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+// line returns the line it is called from. The log calls under test sit on
+// the line after it.
+func line() int {
+	_, _, l, _ := runtime.Caller(1)
+	return l
+}
+
+func TestCallerPointsAtCallSite(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableCaller()
+	sml := logger.NewSystemModuleLogger("Database", "", "")
+
+	var want []int
+	want = append(want, line()+1)
+	logger.Info("info")
+	want = append(want, line()+1)
+	logger.WarnF("%s", "warnf")
+	want = append(want, line()+1)
+	logger.ErrorW("errorw")
+	want = append(want, line()+1)
+	logger.Println("println")
+	want = append(want, line()+1)
+	sml.Debug("module")
+	want = append(want, line()+1)
+	sml.Println("module println")
+	want = append(want, line()+1)
+	sml.InfoCtx(context.Background(), "ctx")
+	want = append(want, line()+1)
+	sml.WarnOnce("test-caller-once", "once")
+	want = append(want, line()+1)
+	slog.New(sml.Handler()).Info("slog")
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(sink.entries))
+	}
+	for i, e := range sink.entries {
+		if !strings.HasSuffix(e.Caller.File, "caller_test.go") || e.Caller.Line != want[i] {
+			t.Errorf("%s: expected caller_test.go:%d, got %s:%d", e.Message, want[i], e.Caller.File, e.Caller.Line)
+		}
+		if !strings.HasSuffix(e.Caller.Function, "TestCallerPointsAtCallSite") {
+			t.Errorf("%s: unexpected function %s", e.Message, e.Caller.Function)
+		}
+		if e.ShortCaller() != fmt.Sprintf("logging/caller_test.go:%d", want[i]) {
+			t.Errorf("Unexpected short caller %s", e.ShortCaller())
+		}
+	}
+}
+
+func TestCallerOfPackageFunctions(t *testing.T) {
+	var buf bytes.Buffer
+	std := logging.Default()
+	std.SetLogger(log.New(&buf, "", 0))
+	defer std.SetLogger(log.Default())
+	std.EnableCaller()
+	std.SetEncoder(logging.JSONEncoder{})
+	defer std.SetEncoder(nil)
+
+	want := line() + 1
+	logging.Warn("package")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON %q", buf.String())
+	}
+	if got["caller"] != fmt.Sprintf("logging/caller_test.go:%d", want) {
+		t.Errorf("Expected the package function's caller, got %v", got["caller"])
+	}
+	if !strings.HasSuffix(got["func"].(string), "TestCallerOfPackageFunctions") {
+		t.Errorf("Unexpected func %v", got["func"])
+	}
+}
+
+func TestStacktraceAtThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+	logger.EnableStacktrace(logging.ERROR)
+
+	logger.Warn("no stack")
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("Entries below the threshold must not carry a stack, got %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.NewSystemModuleLogger("Database", "", "").Error("with stack")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "[ERROR]\t[Database]\twith stack" {
+		t.Errorf("Unexpected first line %q", lines[0])
+	}
+	if len(lines) < 3 || !strings.HasSuffix(lines[1], "TestStacktraceAtThreshold") || !strings.Contains(lines[2], "caller_test.go:") {
+		t.Errorf("Expected the stack to start at the call site, got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "goLogging/logging.(*Logger)") {
+		t.Errorf("The stack should not contain logging internals:\n%s", buf.String())
+	}
+}
+
+func TestStdlogFileFlag(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "app: ", log.Lshortfile|log.Lmsgprefix), logging.DEBUG)
+	logger.DisableTextModifier = true
+	logger.AddHook(func(e *logging.Entry, next func(*logging.Entry)) { next(e) })
+	sml := logger.NewSystemModuleLogger("Database", "", "")
+	sml.AddHook(func(e *logging.Entry, next func(*logging.Entry)) { next(e) })
+
+	var want []int
+	want = append(want, line()+1)
+	logger.Info("info")
+	want = append(want, line()+1)
+	sml.Warn("module")
+	want = append(want, line()+1)
+	slog.New(sml.Handler()).Error("slog")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got %q", len(want), buf.String())
+	}
+	for i, l := range lines {
+		prefix := fmt.Sprintf("caller_test.go:%d: app: [", want[i])
+		if !strings.HasPrefix(l, prefix) {
+			t.Errorf("Expected %q to start with %q", l, prefix)
+		}
+	}
+}
+
+func TestStdlogSharedWithDirectWrites(t *testing.T) {
+	var buf bytes.Buffer
+	std := log.New(&buf, "", log.Lshortfile)
+	logger := logging.NewLogger(std, logging.DEBUG)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			std.Print("direct")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		logger.Info("logger")
+	}
+	<-done
+	if n := strings.Count(buf.String(), "\n"); n != 200 {
+		t.Errorf("Expected 200 lines, got %d", n)
+	}
+}
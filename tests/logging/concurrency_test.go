@@ -0,0 +1,95 @@
+package logging_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestConcurrentModuleLoggersKeepTheirPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+
+	const modules = 8
+	const perModule = 200
+
+	var wg sync.WaitGroup
+	for m := 0; m < modules; m++ {
+		wg.Add(1)
+		go func(m int) {
+			defer wg.Done()
+			name := fmt.Sprintf("Module%d", m)
+			sml := logger.NewSystemModuleLogger(name, logging.Blue, logging.Green)
+			for i := 0; i < perModule; i++ {
+				if i%2 == 0 {
+					sml.Info("from", name)
+				} else {
+					sml.WarnF("from %s", name)
+				}
+			}
+		}(m)
+	}
+	// The general logger writes concurrently with the module loggers.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < perModule; i++ {
+			logger.Error("from General")
+		}
+	}()
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != modules*perModule+perModule {
+		t.Fatalf("Expected %d lines, got %d", modules*perModule+perModule, len(lines))
+	}
+	for _, line := range lines {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 {
+			t.Fatalf("Malformed line %q", line)
+		}
+		module := strings.Trim(parts[1], "[]")
+		if parts[2] != "from "+module {
+			t.Errorf("Line has prefix of %q but message %q", module, parts[2])
+		}
+	}
+}
+
+func TestConcurrentModuleRegistration(t *testing.T) {
+	logger := logging.NewLogger(log.New(&bytes.Buffer{}, "", 0), logging.INFO)
+
+	var wg sync.WaitGroup
+	results := make([]*logging.SystemModuleLogger, 32)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = logger.NewSystemModuleLogger("Shared", "", "")
+			logger.GetSystemModule("Shared")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, sml := range results {
+		if sml != results[0] {
+			t.Fatal("Expected every caller to get the same module logger")
+		}
+	}
+}
+
+func TestLoggerKeepsOriginalPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "app: ", 0), logging.INFO)
+	logger.DisableTextModifier = true
+
+	logger.Info("hello")
+	if buf.String() != "app: [INFO]\t[General]\thello\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,89 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestContextTraceIDAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+
+	ctx := logging.ContextWithTraceID(context.Background(), "4bf92f3577b34da6")
+	ctx = logging.ContextWithFields(ctx, logging.String("user", "alice"))
+
+	logger.InfoCtx(ctx, "request", "started")
+	sml := logger.NewSystemModuleLogger("Database", "", "")
+	sml.ErrorFCtx(ctx, "query took %dms", 1200)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	for _, line := range lines {
+		if !strings.HasSuffix(line, " trace_id=4bf92f3577b34da6 user=alice") {
+			t.Errorf("Expected trace ID and fields from the context, got %q", line)
+		}
+	}
+	if !strings.HasPrefix(lines[1], "[ERROR]\t[Database]\tquery took 1200ms") {
+		t.Errorf("Unexpected line %q", lines[1])
+	}
+}
+
+func TestContextSpanID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+
+	ctx := logging.ContextWithSpanID(context.Background(), "00f067aa0ba902b7")
+	logger.InfoCtx(ctx, "span only")
+	ctx = logging.ContextWithTraceID(ctx, "4bf92f3577b34da6a3ce929d0e0e4736")
+	logger.InfoCtx(ctx, "both")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	if !strings.HasSuffix(lines[0], " span_id=00f067aa0ba902b7") {
+		t.Errorf("Expected the span ID, got %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], " trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7") {
+		t.Errorf("Expected trace ID and span ID, got %q", lines[1])
+	}
+	if logging.SpanIDFromContext(ctx) != "00f067aa0ba902b7" {
+		t.Errorf("Unexpected span ID %q", logging.SpanIDFromContext(ctx))
+	}
+}
+
+func TestContextWithoutValues(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+
+	logger.WarnCtx(context.Background(), "plain")
+	if buf.String() != "[WARN]\t[General]\tplain\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+	if logging.TraceIDFromContext(context.Background()) != "" {
+		t.Error("Expected no trace ID")
+	}
+}
+
+func TestSlogHandlerUsesContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+
+	ctx := logging.ContextWithTraceID(context.Background(), "abc")
+	slog.New(logger.Handler()).InfoContext(ctx, "via slog")
+	if !strings.HasSuffix(buf.String(), " trace_id=abc\n") {
+		t.Errorf("Expected the trace ID of the context, got %q", buf.String())
+	}
+}
@@ -0,0 +1,158 @@
+package logging_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestDedupCollapsesRepeats(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableDedup(time.Hour)
+
+	sml := logger.NewSystemModuleLogger("Database", "", "")
+	for i := 0; i < 1000; i++ {
+		sml.Error("connection refused")
+	}
+	sml.Warn("connection refused") // other level, not a repeat
+	logger.Error("connection refused")
+	logger.Flush()
+
+	got := sink.messages()
+	want := []string{"connection refused", "connection refused", "connection refused", "last message repeated 999 times"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+	sink.mu.Lock()
+	summary := sink.entries[3]
+	sink.mu.Unlock()
+	if summary.Module != "Database" || summary.Level != logging.ERROR {
+		t.Errorf("Summary should keep level and module, got %+v", summary)
+	}
+}
+
+// summarySink records entries and signals every dedup summary.
+type summarySink struct {
+	recordingSink
+	summaries chan struct{}
+}
+
+func (s *summarySink) Write(e *logging.Entry) error {
+	_ = s.recordingSink.Write(e)
+	if strings.HasPrefix(e.Message, "last message repeated") {
+		s.summaries <- struct{}{}
+	}
+	return nil
+}
+
+func TestDedupWindowExpires(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &summarySink{summaries: make(chan struct{}, 1)}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableDedup(30 * time.Millisecond)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	select {
+	case <-sink.summaries:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a summary when the window ends")
+	}
+	logger.Info("tick")
+
+	got := sink.messages()
+	if len(got) != 3 || got[1] != "last message repeated 1 times" || got[2] != "tick" {
+		t.Errorf("Unexpected entries %v", got)
+	}
+}
+
+func TestRateLimitPerCallSite(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableRateLimit(5, time.Hour)
+
+	for i := 0; i < 20; i++ {
+		logger.InfoF("first site %d", i)
+	}
+	for i := 0; i < 3; i++ {
+		logger.Info("second site")
+	}
+
+	first, second := 0, 0
+	for _, msg := range sink.messages() {
+		switch {
+		case msg == "second site":
+			second++
+		case len(msg) > 10 && msg[:10] == "first site":
+			first++
+		}
+	}
+	if first != 5 || second != 3 {
+		t.Errorf("Expected 5 entries from the first and 3 from the second site, got %d and %d", first, second)
+	}
+}
+
+func TestRateLimitReportsSuppressed(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableRateLimit(1, time.Minute)
+	// The limiter measures periods by the entry time, set by this clock
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	logger.AddHook(func(e *logging.Entry, next func(*logging.Entry)) {
+		e.Time = now
+		next(e)
+	})
+
+	for i := 0; i < 4; i++ {
+		if i == 3 {
+			now = now.Add(time.Minute)
+		}
+		logger.Warn("flapping")
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(sink.entries))
+	}
+	fields := sink.entries[1].Fields
+	if len(fields) != 1 || fields[0].Key != "suppressed" || fields[0].Value != 2 {
+		t.Errorf("Expected suppressed=2, got %v", fields)
+	}
+}
+
+func TestWarnOnce(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	sml := logger.NewSystemModuleLogger("Config", "", "")
+
+	for i := 0; i < 3; i++ {
+		logger.WarnOnce("test-warn-once", "deprecated option")
+		sml.WarnOnce("test-warn-once", "deprecated option")
+		sml.ErrorOnce("test-error-once", "missing file")
+	}
+	if got := sink.messages(); len(got) != 2 {
+		t.Errorf("Expected each key to be logged once, got %v", got)
+	}
+
+	// Keys are kept per Logger
+	other := logging.NewLogger(nil, logging.DEBUG)
+	otherSink := &recordingSink{}
+	other.AddSink(otherSink, logging.SinkOptions{})
+	other.WarnOnce("test-warn-once", "deprecated option")
+	if got := otherSink.messages(); len(got) != 1 {
+		t.Errorf("Expected another Logger to log the key again, got %v", got)
+	}
+}
@@ -0,0 +1,106 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.SetEncoder(logging.JSONEncoder{})
+
+	sml := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	sml.ErrorW("query failed",
+		logging.Int("rows", 3),
+		logging.Bool("retry", true),
+		logging.Duration("took", time.Second),
+		logging.Err(errors.New("timeout")),
+	)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	want := map[string]any{
+		"level":  "error",
+		"module": "Database",
+		"msg":    "query failed",
+		"rows":   float64(3),
+		"retry":  true,
+		"took":   "1s",
+		"error":  "timeout",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, got[k])
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, got["time"].(string)); err != nil {
+		t.Errorf("Expected RFC3339 time, got %v", got["time"])
+	}
+}
+
+func TestJSONEncoderKeyOrderAndNoANSI(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.SetEncoder(logging.JSONEncoder{})
+	logger.DisableTextModifier = false
+
+	logger.Fail("boom \"quoted\"")
+
+	output := buf.String()
+	if strings.Contains(output, "\033[") {
+		t.Errorf("JSON output must not contain ANSI codes, got: %q", output)
+	}
+	if !strings.HasPrefix(output, `{"time":`) ||
+		!strings.Contains(output, `,"level":"fail","module":"General","msg":"boom \"quoted\""}`) {
+		t.Errorf("Unexpected JSON layout: %q", output)
+	}
+}
+
+type upperEncoder struct{}
+
+func (upperEncoder) Encode(e *logging.Entry) ([]byte, error) {
+	return []byte(strings.ToUpper(e.ModuleName() + " " + e.Message)), nil
+}
+
+func TestCustomEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.SetEncoder(upperEncoder{})
+	logger.Info("hello")
+	if buf.String() != "GENERAL HELLO\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+
+	// nil restores the default text layout
+	buf.Reset()
+	logger.SetEncoder(nil)
+	logger.DisableTextModifier = true
+	logger.Info("hello")
+	if buf.String() != "[INFO]\t[General]\thello\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestTextEncoderColors(t *testing.T) {
+	line, _ := logging.TextEncoder{}.Encode(&logging.Entry{
+		Level:     logging.WARN,
+		Module:    "HTTP",
+		NameColor: logging.Blue,
+		TextColor: logging.Green,
+		Message:   "slow",
+	})
+	want := string(logging.Yellow) + "[WARN]" + string(logging.Blue) + "\t[HTTP]" + string(logging.Green) + "\tslow" + string(logging.Reset)
+	if string(line) != want {
+		t.Errorf("Expected %q, got %q", want, string(line))
+	}
+}
@@ -0,0 +1,52 @@
+package errorhandling_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/errorhandling"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorSharesTraceIdWithRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+	source := &errorhandling.ErrorSource{Name: "Orders", SML: logger.NewSystemModuleLogger("Orders", "", "")}
+	preset := errorhandling.CustomErrorPreset{
+		Code:       404,
+		LogMessage: "order not found",
+		Source:     source,
+		Level:      errorhandling.ErrorMedium,
+		HttpCode:   404,
+	}
+
+	ctx := logging.ContextWithTraceID(context.Background(), "req-1234")
+	source.SML.InfoCtx(ctx, "loading order")
+
+	r := httptest.NewRequest("GET", "/orders/1", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	preset.New().HandelWeb(w, r)
+
+	assert.Equal(t, "req-1234", w.Header().Get("X-Trace-ID"))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.True(t, strings.HasSuffix(line, " trace_id=req-1234"), line)
+	}
+	assert.True(t, strings.HasPrefix(lines[1], "[ERROR]\t[Orders]\t{trc-req-1234}\torder not found"), lines[1])
+}
+
+func TestNewCtxWithoutTraceId(t *testing.T) {
+	preset := errorhandling.CustomErrorPreset{Code: 500, Source: &errorhandling.GenericErrorsSource}
+	err := preset.NewCtx(context.Background())
+	assert.Len(t, err.TraceId, 16)
+
+	ctx := logging.ContextWithTraceID(context.Background(), "fixed")
+	assert.Equal(t, "fixed", preset.NewCtx(ctx).TraceId)
+}
@@ -0,0 +1,85 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestTraceAndNoticeLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.TRACE)
+	logger.DisableTextModifier = true
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+
+	logger.Trace("trace")
+	db.NoticeW("notice", logging.Int("n", 1))
+	logging.Default().TraceCtx(context.Background(), "hidden at INFO")
+
+	want := "[TRACE]\t[General]\ttrace\n[NOTICE]\t[Database]\tnotice"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	logger.SetLogLevel(logging.NOTICE)
+	logger.Info("hidden")
+	logger.NoticeF("shown %d", 1)
+	if buf.String() != "[NOTICE]\t[General]\tshown 1\n" {
+		t.Errorf("Expected only the NOTICE entry, got %q", buf.String())
+	}
+}
+
+func TestFatal(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.EnableAsync(logging.AsyncOptions{ReportInterval: -1})
+
+	var events []string
+	logging.RegisterShutdownHook(func() {
+		events = append(events, "hook saw "+strings.Join(sink.messages(), ","))
+	})
+	logging.RegisterShutdownHook(func() { panic("broken hook") })
+	logging.RegisterShutdownHook(func() { events = append(events, "second hook") })
+	logger.SetExitFunc(func(code int) { events = append(events, "exit "+strconv.Itoa(code)) })
+
+	logger.Info("before")
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).FatalW("cannot connect", logging.String("host", "db"))
+
+	want := []string{"hook saw before,cannot connect", "second hook", "exit 1"}
+	if strings.Join(events, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %v, got %v", want, events)
+	}
+	if !strings.Contains(buf.String(), "[FAIL]\t[Database]\tcannot connect") {
+		t.Errorf("Expected the FAIL entry, got %q", buf.String())
+	}
+}
+
+func TestPanic(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.INFO)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	exited := false
+	logger.SetExitFunc(func(int) { exited = true })
+
+	defer func() {
+		if r := recover(); r != "state broken 42" {
+			t.Errorf("Expected the message as panic value, got %v", r)
+		}
+		if exited {
+			t.Error("Panic should not exit")
+		}
+		if got := sink.messages(); len(got) != 1 || got[0] != "state broken 42" {
+			t.Errorf("Expected the entry to be logged, got %v", got)
+		}
+	}()
+	logger.PanicF("state broken %d", 42)
+}
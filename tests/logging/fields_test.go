@@ -0,0 +1,145 @@
+package logging_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestLoggerWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+
+	child := logger.With(logging.String("request_id", "abc"), logging.Int("user", 42))
+	child.Info("handled")
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "[INFO]\t[General]\thandled ") {
+		t.Errorf("Unexpected prefix, got: %q", output)
+	}
+	if !strings.HasSuffix(output, " request_id=abc user=42\n") {
+		t.Errorf("Expected fields at the end of the line, got: %q", output)
+	}
+
+	// The parent is unaffected
+	buf.Reset()
+	logger.Info("plain")
+	if buf.String() != "[INFO]\t[General]\tplain\n" {
+		t.Errorf("Parent should not carry child fields, got: %q", buf.String())
+	}
+}
+
+func TestChildSharesLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	child := logger.With(logging.Bool("child", true))
+
+	logger.SetLogLevel(logging.ERROR)
+	child.Warn("hidden")
+	if buf.Len() != 0 {
+		t.Errorf("Child should follow the parent's level, got: %q", buf.String())
+	}
+}
+
+func TestModuleCreatedThroughChild(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+
+	req := logger.With(logging.String("request_id", "req-1"))
+	req.NewSystemModuleLogger("DB", "", "").Info("from request")
+	if !strings.HasSuffix(buf.String(), " request_id=req-1\n") {
+		t.Errorf("Expected the child's fields, got %q", buf.String())
+	}
+
+	// The module belongs to the root Logger, without the child's fields
+	buf.Reset()
+	logger.NewSystemModuleLogger("DB", "", "").Info("plain")
+	logger.GetSystemModule("DB").Info("plain")
+	if buf.String() != "[INFO]\t[DB]\tplain\n[INFO]\t[DB]\tplain\n" {
+		t.Errorf("Root module should not carry child fields, got %q", buf.String())
+	}
+
+	// and follows the root's settings
+	buf.Reset()
+	logger.SetEncoder(logging.LogfmtEncoder{})
+	logger.GetSystemModule("DB").Info("encoded")
+	if !strings.HasSuffix(buf.String(), " level=info module=DB msg=encoded\n") {
+		t.Errorf("Expected the root's encoder, got %q", buf.String())
+	}
+}
+
+func TestChildSharesFeaturesEnabledLater(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+	child := logger.With(logging.Bool("child", true))
+
+	logger.EnableRedaction(logging.RedactOptions{})
+	child.Info("password=hunter2")
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("Expected the child to redact, got %q", buf.String())
+	}
+}
+
+func TestPerCallFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+
+	sml := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).
+		With(logging.String("db", "main"))
+	sml.ErrorW("query failed",
+		logging.Duration("took", 1500*time.Millisecond),
+		logging.Err(errors.New("connection refused")),
+	)
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "[ERROR]\t[Database]\tquery failed") {
+		t.Errorf("Unexpected prefix, got: %q", output)
+	}
+	if !strings.Contains(output, ` db=main took=1.5s error="connection refused"`) {
+		t.Errorf("Expected module and call fields, got: %q", output)
+	}
+}
+
+func TestFieldsAreAligned(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+
+	logger.InfoW("short", logging.Int("n", 1))
+	logger.InfoW("a somewhat longer message", logging.Int("n", 2))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if strings.Index(lines[0], "n=1") != strings.Index(lines[1], "n=2") {
+		t.Errorf("Expected fields in the same column:\n%s\n%s", lines[0], lines[1])
+	}
+}
+
+func TestColoredFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+
+	logger.InfoW("colored", logging.String("key", "value"))
+	if !strings.Contains(buf.String(), string(logging.Cyan)+"key") {
+		t.Errorf("Expected colored field key, got: %q", buf.String())
+	}
+}
+
+func TestFieldKeepsValue(t *testing.T) {
+	err := errors.New("boom")
+	f := logging.Err(err)
+	if f.Key != "error" || f.Value != err {
+		t.Errorf("Expected the error to be kept as data, got %#v", f)
+	}
+}
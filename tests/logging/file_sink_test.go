@@ -0,0 +1,227 @@
+package logging_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFileSinkRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := logging.NewFileSink(logging.FileSinkOptions{
+		Filename: filepath.Join(dir, "app.log"),
+		MaxSize:  100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	for i := 0; i < 5; i++ {
+		logger.Info(strings.Repeat("x", 40))
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := listDir(t, dir)
+	if len(names) < 3 {
+		t.Fatalf("Expected rotated backups, got %v", names)
+	}
+	for _, name := range names {
+		info, _ := os.Stat(filepath.Join(dir, name))
+		if info.Size() > 100 {
+			t.Errorf("%s grew beyond MaxSize: %d bytes", name, info.Size())
+		}
+		if name != "app.log" && !strings.HasPrefix(name, "app-") {
+			t.Errorf("Unexpected backup name %s", name)
+		}
+	}
+}
+
+func TestFileSinkRotatesByTime(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := logging.NewFileSink(logging.FileSinkOptions{
+		Filename: filepath.Join(dir, "app.log"),
+		Interval: logging.RotateHourly,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	// Entries from the next hour on, so the test does not depend on the clock
+	next := time.Now().Truncate(time.Hour).Add(time.Hour)
+	_ = sink.Write(&logging.Entry{Time: next.Add(time.Minute), Level: logging.INFO, Message: "first"})
+	_ = sink.Write(&logging.Entry{Time: next.Add(10 * time.Minute), Level: logging.INFO, Message: "same hour"})
+	if names := listDir(t, dir); len(names) != 1 {
+		t.Fatalf("Expected no rotation within the hour, got %v", names)
+	}
+	_ = sink.Write(&logging.Entry{Time: next.Add(2 * time.Hour), Level: logging.INFO, Message: "later"})
+	if names := listDir(t, dir); len(names) != 2 {
+		t.Fatalf("Expected one backup after the hour changed, got %v", names)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	if !strings.Contains(string(data), "later") || strings.Contains(string(data), "first") {
+		t.Errorf("Expected a fresh file after the hour changed, got %q", data)
+	}
+}
+
+func TestFileSinkCompressesAndKeepsMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := logging.NewFileSink(logging.FileSinkOptions{
+		Filename:   filepath.Join(dir, "app.log"),
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 4; i++ {
+		_ = sink.Write(&logging.Entry{Time: time.Now(), Level: logging.INFO, Message: "rotation"})
+		if err := sink.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := listDir(t, dir)
+	if len(names) != 3 {
+		t.Fatalf("Expected app.log and 2 backups, got %v", names)
+	}
+	for _, name := range names[:2] {
+		if !strings.HasSuffix(name, ".log.gz") {
+			t.Fatalf("Expected compressed backup, got %s", name)
+		}
+		f, _ := os.Open(filepath.Join(dir, name))
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(zr)
+		f.Close()
+		if !strings.Contains(string(data), "rotation") {
+			t.Errorf("Unexpected backup content %q", data)
+		}
+	}
+}
+
+func TestFileSinkMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "app-"+time.Now().Add(-48*time.Hour).Format("2006-01-02T15-04-05.000000000")+".log")
+	if err := os.WriteFile(old, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sink, err := logging.NewFileSink(logging.FileSinkOptions{
+		Filename: filepath.Join(dir, "app.log"),
+		MaxAge:   24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = sink.Rotate()
+	_ = sink.Close()
+
+	for _, name := range listDir(t, dir) {
+		if filepath.Join(dir, name) == old {
+			t.Errorf("Expected the old backup to be removed")
+		}
+	}
+}
+
+func TestFileSinkReopen(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "app.log")
+	sink, err := logging.NewFileSink(logging.FileSinkOptions{Filename: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	_ = sink.Write(&logging.Entry{Level: logging.INFO, Message: "before"})
+	// An external tool moves the file away
+	if err := os.Rename(name, name+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	_ = sink.Write(&logging.Entry{Level: logging.INFO, Message: "after"})
+
+	moved, _ := os.ReadFile(name + ".1")
+	current, _ := os.ReadFile(name)
+	if !strings.Contains(string(moved), "before") || strings.Contains(string(moved), "after") {
+		t.Errorf("Unexpected moved file %q", moved)
+	}
+	if !strings.Contains(string(current), "after") {
+		t.Errorf("Unexpected new file %q", current)
+	}
+}
+
+func TestFileSinkRecoversFromFailedRotation(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	name := filepath.Join(dir, "app.log")
+	sink, err := logging.NewFileSink(logging.FileSinkOptions{Filename: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	if err := sink.Write(&logging.Entry{Level: logging.INFO, Message: "before"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the directory with a file, so neither rename nor open work
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Rotate(); err == nil {
+		t.Fatal("Expected the rotation to fail")
+	}
+	if err := sink.Write(&logging.Entry{Level: logging.INFO, Message: "lost"}); err == nil {
+		t.Fatal("Expected the write to fail while the file cannot be opened")
+	}
+
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(&logging.Entry{Level: logging.INFO, Message: "after"}); err != nil {
+		t.Fatalf("Expected Write to reopen the file, got %v", err)
+	}
+	current, _ := os.ReadFile(name)
+	if !strings.Contains(string(current), "after") {
+		t.Errorf("Unexpected file %q", current)
+	}
+
+	sink.Close()
+	if err := sink.Write(&logging.Entry{Level: logging.INFO, Message: "closed"}); err != os.ErrClosed {
+		t.Errorf("Expected os.ErrClosed after Close, got %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package gelf_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/gelf"
+)
+
+// readMessage reads datagrams until a whole message arrived, reassembling
+// chunks, and decompresses it.
+func readMessage(t *testing.T, conn net.PacketConn) map[string]any {
+	t.Helper()
+	var chunks [][]byte
+	received := 0
+	buf := make([]byte, 65536)
+	var data []byte
+	for data == nil {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		packet := append([]byte(nil), buf[:n]...)
+		if len(packet) < 2 || packet[0] != 0x1e || packet[1] != 0x0f {
+			data = packet
+			break
+		}
+		seq, count := int(packet[10]), int(packet[11])
+		if chunks == nil {
+			chunks = make([][]byte, count)
+		}
+		if chunks[seq] == nil {
+			received++
+		}
+		chunks[seq] = packet[12:]
+		if received == count {
+			data = bytes.Join(chunks, nil)
+		}
+	}
+	return decode(t, data)
+}
+
+func decode(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+	var r io.Reader = bytes.NewReader(data)
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte{0x1f, 0x8b}):
+		r, err = gzip.NewReader(r)
+	case data[0] == 0x78:
+		r, err = zlib.NewReader(r)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg map[string]any
+	if err := json.NewDecoder(r).Decode(&msg); err != nil {
+		t.Fatalf("Invalid message: %v", err)
+	}
+	return msg
+}
+
+func listenUDP(t *testing.T, options gelf.Options) (net.PacketConn, *logging.Logger) {
+	t.Helper()
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	options.Address = server.LocalAddr().String()
+	sink, err := gelf.New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sink.Close() })
+	logger := logging.NewLogger(nil, logging.TRACE)
+	logger.AddSink(sink, logging.SinkOptions{Level: logging.TRACE})
+	return server, logger
+}
+
+func TestMessageOverUDP(t *testing.T) {
+	for name, compression := range map[string]gelf.Compression{"gzip": gelf.CompressGzip, "zlib": gelf.CompressZlib, "none": gelf.CompressNone} {
+		t.Run(name, func(t *testing.T) {
+			server, logger := listenUDP(t, gelf.Options{Host: "web-1", Compression: compression})
+
+			logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).
+				WarnW("slow query", logging.Int("rows", 42), logging.Group("db", logging.String("table", "users")),
+					logging.String("id", "7"), logging.String("user name", "bob"))
+
+			msg := readMessage(t, server)
+			expected := map[string]any{
+				"version":       "1.1",
+				"host":          "web-1",
+				"short_message": "slow query",
+				"level":         4.0,
+				"_module":       "Database",
+				"_rows":         42.0,
+				"_db.table":     "users",
+				"__id":          "7",
+				"_user_name":    "bob",
+			}
+			for key, value := range expected {
+				if msg[key] != value {
+					t.Errorf("Expected %s=%v, got %v", key, value, msg[key])
+				}
+			}
+			if _, ok := msg["full_message"]; ok {
+				t.Errorf("Expected no full_message for a single line, got %v", msg["full_message"])
+			}
+			if ts, ok := msg["timestamp"].(float64); !ok || time.Since(time.Unix(int64(ts), 0)) > time.Minute {
+				t.Errorf("Unexpected timestamp %v", msg["timestamp"])
+			}
+		})
+	}
+}
+
+func TestChunking(t *testing.T) {
+	server, logger := listenUDP(t, gelf.Options{Compression: gelf.CompressNone, ChunkSize: 512})
+
+	message := "first line\n" + strings.Repeat("0123456789", 1000)
+	logger.Error(message)
+
+	msg := readMessage(t, server)
+	if msg["short_message"] != "first line" {
+		t.Errorf("Expected the first line as short_message, got %v", msg["short_message"])
+	}
+	if msg["full_message"] != message {
+		t.Errorf("Expected the whole message as full_message, got %d bytes", len(msg["full_message"].(string)))
+	}
+	if msg["_module"] != "General" || msg["level"] != 3.0 {
+		t.Errorf("Unexpected module or level: %v %v", msg["_module"], msg["level"])
+	}
+}
+
+func TestTooManyChunks(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	sink, err := gelf.New(gelf.Options{Address: server.LocalAddr().String(), Compression: gelf.CompressNone, ChunkSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	if err := sink.Write(&logging.Entry{Level: logging.INFO, Message: strings.Repeat("x", 128*100)}); err == nil {
+		t.Error("Expected an error for a message needing more than 128 chunks")
+	}
+}
+
+func TestMessageOverTCP(t *testing.T) {
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	sink, err := gelf.New(gelf.Options{Network: "tcp", Address: server.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	conn, err := server.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	logger := logging.NewLogger(nil, logging.TRACE)
+	logger.AddSink(sink, logging.SinkOptions{Level: logging.TRACE})
+
+	logger.Notice("first")
+	logger.Trace("second")
+
+	r := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for _, expected := range []struct {
+		message string
+		level   float64
+	}{{"first", 5}, {"second", 7}} {
+		frame, err := r.ReadBytes(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// TCP messages are never compressed
+		msg := decode(t, frame[:len(frame)-1])
+		if msg["short_message"] != expected.message || msg["level"] != expected.level {
+			t.Errorf("Expected %q at level %v, got %v", expected.message, expected.level, msg)
+		}
+	}
+}
+
+func TestFieldsDoNotOverwriteSinkFields(t *testing.T) {
+	server, logger := listenUDP(t, gelf.Options{})
+	logger.EnableCaller()
+
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).
+		InfoW("query", logging.String("module", "orders"), logging.String("file", "a.csv"), logging.Int("line", 7), logging.String("function", "sum"))
+
+	msg := readMessage(t, server)
+	expected := map[string]any{
+		"_module":    "Database",
+		"__module":   "orders",
+		"__file":     "a.csv",
+		"__line":     7.0,
+		"__function": "sum",
+	}
+	for key, value := range expected {
+		if msg[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, msg[key])
+		}
+	}
+	if file, _ := msg["_file"].(string); !strings.HasSuffix(file, "gelf_test.go") {
+		t.Errorf("Expected the caller in _file, got %v", msg["_file"])
+	}
+	if _, ok := msg["_line"].(float64); !ok {
+		t.Errorf("Expected the caller line in _line, got %v", msg["_line"])
+	}
+}
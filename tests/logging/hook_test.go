@@ -0,0 +1,111 @@
+package logging_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestHookMutatesEntries(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+	logger.AddHook(func(e *logging.Entry, next func(*logging.Entry)) {
+		e.Fields = append(e.Fields, logging.String("host", "web-1"))
+		next(e)
+	})
+
+	logger.With(logging.Int("n", 1)).Info("started")
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).Warn("slow")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	if !strings.HasSuffix(lines[0], " n=1 host=web-1") || !strings.HasSuffix(lines[1], " host=web-1") {
+		t.Errorf("Expected the hook field on every entry, got %q", lines)
+	}
+}
+
+func TestHookDropsAndDuplicates(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	var alerts []string
+	logger.AddHook(func(e *logging.Entry, next func(*logging.Entry)) {
+		if e.Message == "GET /health" {
+			return
+		}
+		next(e)
+	})
+	logger.AddHook(func(e *logging.Entry, next func(*logging.Entry)) {
+		if e.Level.Severity() >= logging.FAIL.Severity() {
+			alerts = append(alerts, e.Message)
+			alert := e.Clone()
+			alert.Message = "[alert] " + e.Message
+			next(alert)
+		}
+		next(e)
+	})
+
+	logger.Info("GET /health")
+	logger.Info("GET /users")
+	logger.Fail("disk full")
+
+	if got := strings.Join(sink.messages(), ","); got != "GET /users,[alert] disk full,disk full" {
+		t.Errorf("Unexpected entries %s", got)
+	}
+	if len(alerts) != 1 || alerts[0] != "disk full" {
+		t.Errorf("Expected one alert, got %v", alerts)
+	}
+}
+
+func TestHooksSkipDisabledLevels(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.WARN)
+	logger.AddSink(&recordingSink{}, logging.SinkOptions{Level: logging.WARN})
+	calls := 0
+	logger.AddHook(func(e *logging.Entry, next func(*logging.Entry)) {
+		calls++
+		next(e)
+	})
+	logger.Info("below the level")
+	if calls != 0 {
+		t.Errorf("Hooks should run after the level check, ran %d times", calls)
+	}
+}
+
+func TestModuleHooks(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.INFO)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	var order []string
+	tag := func(name string) logging.Hook {
+		return func(e *logging.Entry, next func(*logging.Entry)) {
+			order = append(order, name+":"+e.Message)
+			next(e)
+		}
+	}
+	logger.AddHook(tag("logger"))
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	db.AddHook(tag("db"))
+	pool := db.NewChild("Pool")
+	pool.AddHook(tag("pool"))
+	http := logger.NewSystemModuleLogger("HTTP", logging.Blue, logging.Green)
+
+	pool.Info("a")
+	db.With(logging.Int("n", 1)).Info("b")
+	http.Info("c")
+
+	want := "logger:a,db:a,pool:a,logger:b,db:b,logger:c"
+	if got := strings.Join(order, ","); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if len(sink.messages()) != 3 {
+		t.Errorf("Expected 3 entries, got %v", sink.messages())
+	}
+}
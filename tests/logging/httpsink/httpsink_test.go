@@ -0,0 +1,503 @@
+package httpsink_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/httpsink"
+)
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// server records requests and answers with the given status codes in turn,
+// then with 200. Every request is also signaled on arrived.
+type server struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []request
+	statuses []int
+	response string
+	arrived  chan struct{}
+}
+
+func newServer(t *testing.T, statuses ...int) *server {
+	s := &server{statuses: statuses, arrived: make(chan struct{}, 100)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			body = zr
+		}
+		data, _ := io.ReadAll(body)
+		s.mu.Lock()
+		s.requests = append(s.requests, request{r.Header.Clone(), data})
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		response := s.response
+		s.mu.Unlock()
+		select {
+		case s.arrived <- struct{}{}:
+		default:
+		}
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// wait blocks until the next request arrived.
+func (s *server) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.arrived:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a request")
+	}
+}
+
+func (s *server) received() []request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]request(nil), s.requests...)
+}
+
+func newSink(t *testing.T, options httpsink.Options) (*logging.Logger, *httpsink.Sink) {
+	t.Helper()
+	sink, err := httpsink.New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sink.Close(context.Background()) })
+	logger := logging.NewLogger(nil, logging.TRACE)
+	logger.AddSink(sink, logging.SinkOptions{})
+	return logger, sink
+}
+
+type lokiPush struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+func TestLokiBatchBySize(t *testing.T) {
+	srv := newServer(t)
+	logger, _ := newSink(t, httpsink.Options{
+		URL:           srv.URL,
+		Format:        httpsink.Loki{Labels: map[string]string{"app": "shop"}},
+		Gzip:          true,
+		BatchSize:     3,
+		FlushInterval: time.Hour,
+	})
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+
+	logger.Info("one")
+	db.InfoW("two", logging.Int("rows", 2))
+	if len(srv.received()) != 0 {
+		t.Fatal("Expected no request before the batch is full")
+	}
+	logger.Info("three")
+
+	srv.wait(t)
+	requests := srv.received()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	if requests[0].header.Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected content type %q", requests[0].header.Get("Content-Type"))
+	}
+	var push lokiPush
+	if err := json.Unmarshal(requests[0].body, &push); err != nil {
+		t.Fatal(err)
+	}
+	if len(push.Streams) != 2 {
+		t.Fatalf("Expected 2 streams, got %s", requests[0].body)
+	}
+	database, general := push.Streams[0], push.Streams[1]
+	if database.Stream["module"] != "Database" || database.Stream["level"] != "info" || database.Stream["app"] != "shop" {
+		t.Errorf("Unexpected labels %v", database.Stream)
+	}
+	if len(database.Values) != 1 || !strings.Contains(database.Values[0][1], "msg=two rows=2") {
+		t.Errorf("Unexpected values %v", database.Values)
+	}
+	if general.Stream["module"] != "General" || len(general.Values) != 2 {
+		t.Errorf("Unexpected stream %v", general)
+	}
+}
+
+func TestFlushInterval(t *testing.T) {
+	srv := newServer(t)
+	logger, _ := newSink(t, httpsink.Options{URL: srv.URL, Format: httpsink.Loki{}, FlushInterval: 20 * time.Millisecond})
+
+	logger.Warn("waiting")
+	srv.wait(t)
+	if len(srv.received()) != 1 {
+		t.Fatalf("Expected the batch after the flush interval, got %d requests", len(srv.received()))
+	}
+}
+
+func TestBatchBytes(t *testing.T) {
+	srv := newServer(t)
+	logger, sink := newSink(t, httpsink.Options{URL: srv.URL, Format: httpsink.Loki{}, BatchBytes: 1000, FlushInterval: time.Hour})
+
+	for i := 0; i < 4; i++ {
+		logger.Info(strings.Repeat("x", 400))
+	}
+	sink.Flush()
+	if n := len(srv.received()); n != 2 {
+		t.Errorf("Expected 2 batches of about 1000 bytes, got %d", n)
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	srv := newServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	var errs []error
+	logger, sink := newSink(t, httpsink.Options{
+		URL:        srv.URL,
+		Format:     httpsink.Loki{},
+		MinBackoff: time.Millisecond,
+		OnError:    func(err error) { errs = append(errs, err) },
+	})
+
+	logger.Error("retried")
+	sink.Flush()
+	if n := len(srv.received()); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+	if len(errs) != 0 || sink.Dropped() != 0 {
+		t.Errorf("Expected the batch to be delivered, got %v and %d dropped", errs, sink.Dropped())
+	}
+}
+
+func TestRetryAfterIsCapped(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch attempts.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("Retry-After", "Mon, 02 Jan 2006 15:04:05 GMT")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	logger, sink := newSink(t, httpsink.Options{
+		URL:        srv.URL,
+		Format:     httpsink.Loki{},
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	})
+
+	logger.Error("throttled")
+	start := time.Now()
+	sink.Flush()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected Retry-After to be capped by MaxBackoff, waited %v", elapsed)
+	}
+	if n := attempts.Load(); n != 3 || sink.Dropped() != 0 {
+		t.Errorf("Expected delivery on the 3rd attempt, got %d attempts and %d dropped", n, sink.Dropped())
+	}
+}
+
+func TestConcurrentFlush(t *testing.T) {
+	srv := newServer(t)
+	logger, sink := newSink(t, httpsink.Options{URL: srv.URL, Format: httpsink.Loki{}, BatchSize: 3, QueueSize: 64})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				logger.Info("entry")
+				if j%5 == 0 {
+					sink.Flush()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	sink.Flush()
+
+	var entries int
+	for _, r := range srv.received() {
+		var push lokiPush
+		if err := json.Unmarshal(r.body, &push); err != nil {
+			t.Fatal(err)
+		}
+		for _, stream := range push.Streams {
+			entries += len(stream.Values)
+		}
+	}
+	if entries+int(sink.Dropped()) != 160 {
+		t.Errorf("Expected 160 entries delivered or dropped, got %d and %d", entries, sink.Dropped())
+	}
+}
+
+func TestOnErrorCanLogToTheSink(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	var logger *logging.Logger
+	var reported atomic.Bool
+	logger, sink := newSink(t, httpsink.Options{
+		URL:       srv.URL,
+		Format:    httpsink.Loki{},
+		BatchSize: 1,
+		QueueSize: 1,
+		OnError: func(err error) {
+			// Logs to the sink that reports the error, once
+			if reported.CompareAndSwap(false, true) {
+				logger.Error(err.Error())
+			}
+		},
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 4; i++ {
+			logger.Info("entry")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Write deadlocked when OnError logged to the sink")
+	}
+	close(release)
+	sink.Flush()
+	if !reported.Load() {
+		t.Error("Expected the full queue to be reported")
+	}
+}
+
+func TestPermanentFailure(t *testing.T) {
+	srv := newServer(t, http.StatusBadRequest)
+	var errs []error
+	logger, sink := newSink(t, httpsink.Options{
+		URL:        srv.URL,
+		Format:     httpsink.Loki{},
+		MinBackoff: time.Millisecond,
+		OnError:    func(err error) { errs = append(errs, err) },
+	})
+
+	logger.Info("one")
+	logger.Info("two")
+	sink.Flush()
+	if n := len(srv.received()); n != 1 {
+		t.Errorf("Expected no retries of a 400, got %d attempts", n)
+	}
+	if len(errs) != 1 || sink.Dropped() != 2 {
+		t.Errorf("Expected 1 error and 2 dropped entries, got %v and %d", errs, sink.Dropped())
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	srv := newServer(t, 500, 500, 500)
+	var errs []error
+	logger, sink := newSink(t, httpsink.Options{
+		URL:        srv.URL,
+		Format:     httpsink.Loki{},
+		MaxRetries: 2,
+		MinBackoff: time.Millisecond,
+		OnError:    func(err error) { errs = append(errs, err) },
+	})
+
+	logger.Info("lost")
+	sink.Flush()
+	if n := len(srv.received()); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+	if len(errs) != 1 || sink.Dropped() != 1 {
+		t.Errorf("Expected the batch to be dropped, got %v and %d", errs, sink.Dropped())
+	}
+}
+
+func TestElasticsearchBulk(t *testing.T) {
+	srv := newServer(t)
+	var errs []error
+	logger, sink := newSink(t, httpsink.Options{
+		URL:     srv.URL,
+		Format:  httpsink.Elasticsearch{Index: "logs-shop"},
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).
+		WarnW("slow query", logging.Int("rows", 42), logging.Group("db", logging.String("table", "users")), logging.Bool("cached", false),
+			logging.String("message", "fake"), logging.String("level", "debug"))
+	logger.Info("second")
+	sink.Flush()
+
+	requests := srv.received()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	if ct := requests[0].header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+	var lines []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(requests[0].body))
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("Invalid line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("Expected 2 actions and 2 documents, got %d lines", len(lines))
+	}
+	action := lines[0]["create"].(map[string]any)
+	if action["_index"] != "logs-shop" {
+		t.Errorf("Unexpected action %v", lines[0])
+	}
+	doc := lines[1]
+	expected := map[string]any{"level": "warn", "module": "Database", "message": "slow query", "rows": 42.0, "db.table": "users", "cached": false,
+		"fields.message": "fake", "fields.level": "debug"}
+	for key, value := range expected {
+		if doc[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, doc[key])
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, doc["@timestamp"].(string)); err != nil {
+		t.Errorf("Invalid @timestamp: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+}
+
+func TestElasticsearchRejectedDocuments(t *testing.T) {
+	srv := newServer(t)
+	srv.response = `{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [rows]"}}}]}`
+	var errs []error
+	logger, sink := newSink(t, httpsink.Options{
+		URL:     srv.URL,
+		Format:  httpsink.Elasticsearch{},
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	logger.Info("one")
+	logger.Info("two")
+	sink.Flush()
+	if len(srv.received()) != 1 {
+		t.Errorf("Expected rejected documents not to be retried, got %d requests", len(srv.received()))
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "1 of 2 documents") || !strings.Contains(errs[0].Error(), "mapper_parsing_exception") {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+	if sink.Dropped() != 1 {
+		t.Errorf("Expected only the rejected document to count as dropped, got %d", sink.Dropped())
+	}
+}
+
+func TestSplunkHEC(t *testing.T) {
+	srv := newServer(t)
+	logger, sink := newSink(t, httpsink.Options{
+		URL:    srv.URL,
+		Format: httpsink.Splunk{Host: "web-1", SourceType: "_json", Index: "main"},
+		Header: http.Header{"Authorization": {"Splunk 0000-token"}},
+	})
+
+	logger.NoticeW("order placed", logging.String(logging.TraceIDKey, "abc"), logging.Any("total", 9.5))
+	sink.Flush()
+
+	requests := srv.received()
+	if len(requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(requests))
+	}
+	if auth := requests[0].header.Get("Authorization"); auth != "Splunk 0000-token" {
+		t.Errorf("Expected the configured header, got %q", auth)
+	}
+	var event struct {
+		Time       float64        `json:"time"`
+		Host       string         `json:"host"`
+		SourceType string         `json:"sourcetype"`
+		Index      string         `json:"index"`
+		Source     *string        `json:"source"`
+		Event      map[string]any `json:"event"`
+	}
+	if err := json.Unmarshal(requests[0].body, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Host != "web-1" || event.SourceType != "_json" || event.Index != "main" || event.Source != nil {
+		t.Errorf("Unexpected metadata %+v", event)
+	}
+	if time.Since(time.Unix(int64(event.Time), 0)) > time.Minute {
+		t.Errorf("Unexpected time %v", event.Time)
+	}
+	expected := map[string]any{"level": "notice", "module": "General", "message": "order placed", "trace_id": "abc", "total": 9.5}
+	for key, value := range expected {
+		if event.Event[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, event.Event[key])
+		}
+	}
+}
+
+func TestCloseSendsPendingEntries(t *testing.T) {
+	srv := newServer(t)
+	sink, err := httpsink.New(httpsink.Options{URL: srv.URL, Format: httpsink.Loki{}, FlushInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.NewLogger(nil, logging.TRACE)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	logger.Info("pending")
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.received()) != 1 {
+		t.Errorf("Expected the pending batch to be sent on Close, got %d requests", len(srv.received()))
+	}
+	if err := sink.Write(&logging.Entry{Message: "late"}); err != httpsink.ErrClosed {
+		t.Errorf("Expected ErrClosed after Close, got %v", err)
+	}
+}
+
+func TestCloseAbandonsRetries(t *testing.T) {
+	srv := newServer(t, 503)
+	sink, err := httpsink.New(httpsink.Options{URL: srv.URL, Format: httpsink.Loki{}, MinBackoff: time.Hour, OnError: func(error) {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(&logging.Entry{Level: logging.INFO, Message: "stuck"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := sink.Close(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected the deadline error, got %v", err)
+	}
+	if sink.Dropped() != 1 {
+		t.Errorf("Expected the abandoned entry to count as dropped, got %d", sink.Dropped())
+	}
+}
@@ -0,0 +1,196 @@
+//go:build linux
+
+package journald_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/journald"
+)
+
+// listen returns a local journal socket and a sink connected to it.
+func listen(t *testing.T) (*net.UnixConn, *journald.Sink) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	server, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.Close() })
+	sink, err := journald.New(journald.Options{Socket: path, Identifier: "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sink.Close() })
+	return server, sink
+}
+
+// read receives one entry, following a passed file descriptor.
+func read(t *testing.T, server *net.UnixConn) []byte {
+	t.Helper()
+	buf := make([]byte, 1<<16)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_ = server.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, oobn, _, _, err := server.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oobn == 0 {
+		return buf[:n]
+	}
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := os.NewFile(uintptr(fds[0]), "entry")
+	defer f.Close()
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// parse decodes the native protocol into fields.
+func parse(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	fields := map[string]string{}
+	for len(data) > 0 {
+		i := bytes.IndexAny(data, "=\n")
+		if i < 0 {
+			t.Fatalf("Truncated entry %q", data)
+		}
+		key := string(data[:i])
+		if data[i] == '=' {
+			end := bytes.IndexByte(data, '\n')
+			fields[key] = string(data[i+1 : end])
+			data = data[end+1:]
+			continue
+		}
+		n := binary.LittleEndian.Uint64(data[i+1 : i+9])
+		fields[key] = string(data[i+9 : i+9+int(n)])
+		if data[i+9+int(n)] != '\n' {
+			t.Fatalf("Missing newline after binary field %s", key)
+		}
+		data = data[i+10+int(n):]
+	}
+	return fields
+}
+
+func TestNativeProtocol(t *testing.T) {
+	server, sink := listen(t)
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).
+		WarnW("slow query", logging.String(logging.TraceIDKey, "abc123"), logging.Group("db", logging.Int("pool", 2)),
+			logging.String("query", "SELECT *\nFROM users"), logging.Int("1st", 1), logging.String("priority", "high"))
+
+	fields := parse(t, read(t, server))
+	expected := map[string]string{
+		"MESSAGE":           "slow query",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "shop",
+		"MODULE":            "Database",
+		"TRACE_ID":          "abc123",
+		"DB_POOL":           "2",
+		"QUERY":             "SELECT *\nFROM users",
+		"F_1ST":             "1",
+		"F_PRIORITY":        "high",
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Expected %s=%q, got %q", key, value, fields[key])
+		}
+	}
+
+	logger.Error("no module")
+	fields = parse(t, read(t, server))
+	if fields["PRIORITY"] != "3" || fields["MESSAGE"] != "no module" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	if _, ok := fields["MODULE"]; ok {
+		t.Errorf("Expected no MODULE for the Logger, got %q", fields["MODULE"])
+	}
+}
+
+func TestLargeEntryIsPassedAsFile(t *testing.T) {
+	server, sink := listen(t)
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	message := strings.Repeat("x", 1<<20)
+	logger.Info(message)
+
+	fields := parse(t, read(t, server))
+	if fields["MESSAGE"] != message {
+		t.Errorf("Expected a message of %d bytes, got %d", len(message), len(fields["MESSAGE"]))
+	}
+	if fields["PRIORITY"] != "6" {
+		t.Errorf("Expected PRIORITY=6, got %q", fields["PRIORITY"])
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	cases := []struct{ key, name string }{
+		{"user_id", "USER_ID"},
+		{"http.status", "HTTP_STATUS"},
+		{"_private", "PRIVATE"},
+		{"2fa", "F_2FA"},
+		{"äöü", "F_"},
+		{strings.Repeat("a", 70), strings.Repeat("A", 64)},
+		{"message", "F_MESSAGE"},
+		{"priority", "F_PRIORITY"},
+		{"syslog.identifier", "F_SYSLOG_IDENTIFIER"},
+		{"code_file", "F_CODE_FILE"},
+		{"code_line", "F_CODE_LINE"},
+		{"module", "F_MODULE"},
+		{"message_text", "MESSAGE_TEXT"},
+	}
+	for _, c := range cases {
+		if name := journald.FieldName(c.key); name != c.name {
+			t.Errorf("FieldName(%q) = %q, expected %q", c.key, name, c.name)
+		}
+	}
+}
+
+func TestPrefixSink(t *testing.T) {
+	var out bytes.Buffer
+	logger := logging.NewLogger(nil, logging.TRACE)
+	logger.AddSink(journald.NewPrefixSink(&out, nil), logging.SinkOptions{Level: logging.TRACE})
+
+	logger.Notice("started")
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).FailW("lost\nconnection", logging.Int("retries", 3))
+	logger.Trace("details")
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	expected := []string{"<5>[NOTICE]\t[General]\tstarted", "<2>[FAIL]\t[Database]\tlost", "<2>connection", "<7>[TRACE]\t[General]\tdetails"}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), out.String())
+	}
+	for i, prefix := range expected {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("Expected line %d to start with %q, got %q", i, prefix, lines[i])
+		}
+	}
+	if !strings.HasSuffix(lines[2], "retries=3") {
+		t.Errorf("Expected the fields on the last line of the message, got %q", lines[2])
+	}
+}
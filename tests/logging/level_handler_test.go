@@ -0,0 +1,175 @@
+package logging_test
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func levelsState(t *testing.T, h http.Handler) logging.LevelsState {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET returned %d: %s", rec.Code, rec.Body)
+	}
+	var state logging.LevelsState
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func putLevel(h http.Handler, values url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(values.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLevelHandlerListsModules(t *testing.T) {
+	logger := logging.NewLogger(log.New(io.Discard, "", 0), logging.INFO)
+	logger.NewSystemModuleLogger("HTTP", logging.Blue, logging.Green)
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).SetLogLevel(logging.DEBUG)
+
+	state := levelsState(t, logger.LevelHandler())
+	if state.Logger.Level != logging.INFO {
+		t.Errorf("Expected logger level INFO, got %v", state.Logger.Level)
+	}
+	want := []logging.LevelState{
+		{Name: "Database", Level: logging.DEBUG, Overridden: true},
+		{Name: "HTTP", Level: logging.INFO, Overridden: false},
+	}
+	if len(state.Modules) != len(want) {
+		t.Fatalf("Expected %d modules, got %+v", len(want), state.Modules)
+	}
+	for i := range want {
+		if state.Modules[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], state.Modules[i])
+		}
+	}
+}
+
+func TestLevelHandlerChangesLevels(t *testing.T) {
+	logger := logging.NewLogger(log.New(io.Discard, "", 0), logging.INFO)
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	h := logger.LevelHandler()
+
+	if rec := putLevel(h, url.Values{"module": {"Database"}, "level": {"debug"}}); rec.Code != http.StatusOK {
+		t.Fatalf("PUT returned %d: %s", rec.Code, rec.Body)
+	}
+	if db.GetLogLevel() != logging.DEBUG || !db.LevelOverridden() {
+		t.Errorf("Expected Database at DEBUG, got %v", db.GetLogLevel())
+	}
+
+	if rec := putLevel(h, url.Values{"level": {"error"}}); rec.Code != http.StatusOK {
+		t.Fatalf("PUT returned %d: %s", rec.Code, rec.Body)
+	}
+	if logger.GetLogLevel() != logging.ERROR {
+		t.Errorf("Expected logger level ERROR, got %v", logger.GetLogLevel())
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/?module=Database&level=reset", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST returned %d: %s", rec.Code, rec.Body)
+	}
+	if db.LevelOverridden() || db.GetLogLevel() != logging.ERROR {
+		t.Errorf("Expected Database to inherit ERROR, got %v", db.GetLogLevel())
+	}
+}
+
+func TestLevelHandlerErrors(t *testing.T) {
+	logger := logging.NewLogger(log.New(io.Discard, "", 0), logging.INFO)
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	h := logger.LevelHandler()
+
+	cases := []struct {
+		values url.Values
+		code   int
+	}{
+		{url.Values{"module": {"Nope"}, "level": {"debug"}}, http.StatusNotFound},
+		{url.Values{"module": {"Database"}, "level": {"loud"}}, http.StatusBadRequest},
+		{url.Values{"module": {"Database"}}, http.StatusBadRequest},
+		{url.Values{"level": {"reset"}}, http.StatusBadRequest},
+		{url.Values{"module": {"Database"}, "level": {"debug"}, "revert": {"soon"}}, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		if rec := putLevel(h, c.values); rec.Code != c.code {
+			t.Errorf("%v: expected %d, got %d", c.values, c.code, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for DELETE, got %d", rec.Code)
+	}
+}
+
+func TestLevelHandlerRevert(t *testing.T) {
+	logger := logging.NewLogger(log.New(io.Discard, "", 0), logging.INFO)
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	db.SetLogLevel(logging.WARN)
+	h := logger.LevelHandler()
+
+	putLevel(h, url.Values{"module": {"Database"}, "level": {"debug"}, "revert": {"50ms"}})
+	// A second temporary change still reverts to the original level
+	putLevel(h, url.Values{"module": {"Database"}, "level": {"info"}, "revert": {"50ms"}})
+	if state := levelsState(t, h); state.Modules[0].RevertAt == nil {
+		t.Error("Expected revert_at while the change is temporary")
+	}
+	if db.GetLogLevel() != logging.INFO {
+		t.Fatalf("Expected INFO, got %v", db.GetLogLevel())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for db.GetLogLevel() != logging.WARN && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if db.GetLogLevel() != logging.WARN {
+		t.Errorf("Expected the level to revert to WARN, got %v", db.GetLogLevel())
+	}
+	if state := levelsState(t, h); state.Modules[0].RevertAt != nil {
+		t.Error("Expected no revert_at after reverting")
+	}
+}
+
+func TestLevelChangesWhileLogging(t *testing.T) {
+	logger := logging.NewLogger(log.New(io.Discard, "", 0), logging.INFO)
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	child := db.With(logging.String("db", "main"))
+	h := logger.LevelHandler()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				child.Debug("tick")
+				logger.Info("tick")
+			}
+		}()
+	}
+	for _, level := range []string{"debug", "error", "reset", "warn"} {
+		putLevel(h, url.Values{"module": {"Database"}, "level": {level}})
+		putLevel(h, url.Values{"level": {level}})
+	}
+	wg.Wait()
+
+	if child.GetLogLevel() != logging.WARN {
+		t.Errorf("Expected With copies to share the module level, got %v", child.GetLogLevel())
+	}
+}
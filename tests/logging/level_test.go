@@ -0,0 +1,127 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestParseLevel(t *testing.T) {
+	cases := map[string]logging.LogLevel{
+		"debug":   logging.DEBUG,
+		"INFO":    logging.INFO,
+		" Warn ":  logging.WARN,
+		"warning": logging.WARN,
+		"error":   logging.ERROR,
+		"fail":    logging.FAIL,
+		"off":     logging.NONE,
+		"trace":   logging.TRACE,
+		"Notice":  logging.NOTICE,
+		"3":       logging.ERROR,
+	}
+	for s, want := range cases {
+		got, err := logging.ParseLevel(s)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "verbose", "6", "-1"} {
+		if _, err := logging.ParseLevel(s); err == nil {
+			t.Errorf("ParseLevel(%q) should fail", s)
+		}
+	}
+}
+
+func TestLevelJSONAndFlag(t *testing.T) {
+	var config struct {
+		Level logging.LogLevel `json:"level"`
+	}
+	if err := json.Unmarshal([]byte(`{"level":"warn"}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Level != logging.WARN {
+		t.Errorf("Expected WARN, got %v", config.Level)
+	}
+	out, err := json.Marshal(config)
+	if err != nil || string(out) != `{"level":"warn"}` {
+		t.Errorf("Unexpected JSON %s, %v", out, err)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	level := logging.INFO
+	fs.Var(&level, "level", "")
+	if err := fs.Parse([]string{"-level", "debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if level != logging.DEBUG {
+		t.Errorf("Expected DEBUG from the flag, got %v", level)
+	}
+}
+
+func TestParseLevelSpec(t *testing.T) {
+	spec, err := logging.ParseLevelSpec("info, Database=debug,HTTP=WARN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !spec.HasDefault || spec.Default != logging.INFO {
+		t.Errorf("Expected default INFO, got %+v", spec)
+	}
+	if spec.Modules["Database"] != logging.DEBUG || spec.Modules["HTTP"] != logging.WARN {
+		t.Errorf("Unexpected module levels %v", spec.Modules)
+	}
+	if spec.String() != "info,Database=debug,HTTP=warn" {
+		t.Errorf("Unexpected String() %q", spec.String())
+	}
+
+	for _, s := range []string{"info,Database=loud", "=debug"} {
+		if _, err := logging.ParseLevelSpec(s); err == nil {
+			t.Errorf("ParseLevelSpec(%q) should fail", s)
+		}
+	}
+}
+
+func TestSetLevelSpec(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+
+	if err := logger.SetLevelSpec("error,Database=debug,HTTP=warn"); err != nil {
+		t.Fatal(err)
+	}
+	http := logger.NewSystemModuleLogger("HTTP", logging.Blue, logging.Green)
+
+	if logger.GetLogLevel() != logging.ERROR {
+		t.Errorf("Expected logger level ERROR, got %v", logger.GetLogLevel())
+	}
+	if db.GetLogLevel() != logging.DEBUG {
+		t.Errorf("Expected Database at DEBUG, got %v", db.GetLogLevel())
+	}
+	if http.GetLogLevel() != logging.WARN {
+		t.Errorf("Expected HTTP created later to get WARN, got %v", http.GetLogLevel())
+	}
+
+	if err := logger.SetLevelSpec("Database=nope"); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+	if db.GetLogLevel() != logging.DEBUG {
+		t.Error("An invalid spec should not change any level")
+	}
+}
+
+func TestConfigureFromEnv(t *testing.T) {
+	logger := logging.Default()
+	defer logger.SetLogLevel(logger.GetLogLevel())
+
+	t.Setenv(logging.LevelEnvVar, "fail")
+	if err := logging.ConfigureFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if logger.GetLogLevel() != logging.FAIL {
+		t.Errorf("Expected FAIL from %s, got %v", logging.LevelEnvVar, logger.GetLogLevel())
+	}
+}
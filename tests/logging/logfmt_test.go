@@ -0,0 +1,58 @@
+package logging_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.SetEncoder(logging.LogfmtEncoder{})
+
+	sml := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	sml.InfoW("Connected", logging.String("db", "main"), logging.Duration("took", 20*time.Millisecond))
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "ts=") {
+		t.Errorf("Expected ts first, got: %q", output)
+	}
+	if !strings.HasSuffix(output, " level=info module=Database msg=Connected db=main took=20ms\n") {
+		t.Errorf("Unexpected logfmt output: %q", output)
+	}
+}
+
+func TestLogfmtQuoting(t *testing.T) {
+	line, _ := logging.LogfmtEncoder{TimeFormat: "2006"}.Encode(&logging.Entry{
+		Time:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Level:   logging.FAIL,
+		Message: "connection \"db\" lost\nretrying",
+		Fields: []logging.Field{
+			logging.String("empty", ""),
+			logging.String("expr", "a=b"),
+			logging.String("path", `C:\tmp`),
+			logging.Err(errors.New("dial tcp: refused")),
+			logging.String("bad key", "v"),
+		},
+	})
+	want := `ts=2024 level=fail module=General msg="connection \"db\" lost\nretrying" empty="" expr="a=b" path=C:\tmp error="dial tcp: refused" bad_key=v`
+	if string(line) != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, line)
+	}
+}
+
+func TestLogfmtPrintfLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.NONE)
+	logger.SetEncoder(logging.LogfmtEncoder{})
+	logger.Printf("raw %d", 1)
+	if !strings.Contains(buf.String(), " level=???? module=General msg=\"raw 1\"") {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,89 @@
+package logtest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/logtest"
+)
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	errors []string
+	fatal  bool
+	logs   []string
+}
+
+func (f *fakeT) Helper() {}
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+func (f *fakeT) Fatalf(format string, args ...any) {
+	f.Errorf(format, args...)
+	f.fatal = true
+}
+func (f *fakeT) Log(args ...any) {
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+func (f *fakeT) Cleanup(func()) {}
+
+func TestRecorder(t *testing.T) {
+	logger, rec := logtest.New()
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+
+	logger.Trace("starting")
+	db.ErrorW("query failed", logging.String("table", "users"))
+
+	e := rec.RequireLogged(t, logging.ERROR, "Database", "query")
+	if len(e.Fields) != 1 || e.Fields[0].Value != "users" {
+		t.Errorf("Expected the fields of the entry, got %v", e.Fields)
+	}
+	rec.RequireLogged(t, logging.TRACE, "General", "starting")
+	rec.NoEntriesAbove(t, logging.ERROR)
+
+	if len(rec.Entries()) != 2 {
+		t.Errorf("Expected 2 entries, got %d", len(rec.Entries()))
+	}
+	rec.Reset()
+	if len(rec.Entries()) != 0 {
+		t.Error("Expected no entries after Reset")
+	}
+}
+
+func TestRequireLoggedFails(t *testing.T) {
+	logger, rec := logtest.New()
+	logger.Info("connected")
+
+	ft := &fakeT{TB: t}
+	rec.RequireLogged(ft, logging.INFO, "Database", "connected")
+	if !ft.fatal || !strings.Contains(ft.errors[0], "[INFO]\t[General]\tconnected") {
+		t.Errorf("Expected a fatal failure listing the entries, got %v", ft.errors)
+	}
+}
+
+func TestNoEntriesAbove(t *testing.T) {
+	logger, rec := logtest.New()
+	logger.Warn("slow")
+	logger.Error("failed")
+	logger.Printf("no level")
+
+	ft := &fakeT{TB: t}
+	rec.NoEntriesAbove(ft, logging.WARN)
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "failed") {
+		t.Errorf("Expected one error for the ERROR entry, got %v", ft.errors)
+	}
+}
+
+func TestNewT(t *testing.T) {
+	ft := &fakeT{TB: t}
+	logger, rec := logtest.NewT(ft)
+	logger.WarnW("disk almost full", logging.Int("percent", 91))
+
+	rec.RequireLogged(t, logging.WARN, "", "disk")
+	if len(ft.logs) != 1 || !strings.HasPrefix(ft.logs[0], "[WARN]\t[General]\tdisk almost full") {
+		t.Errorf("Expected the entry in t.Log, got %q", ft.logs)
+	}
+}
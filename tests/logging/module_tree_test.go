@@ -0,0 +1,117 @@
+package logging_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestChildModuleName(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).
+		With(logging.String("db", "main"))
+	pool := db.NewChild("Pool")
+	pool.Info("connected")
+
+	if !strings.HasPrefix(buf.String(), "[INFO]\t[Database.Pool]\tconnected") {
+		t.Errorf("Expected the module path, got: %q", buf.String())
+	}
+	if !strings.HasSuffix(buf.String(), " db=main\n") {
+		t.Errorf("Expected the parent's fields, got: %q", buf.String())
+	}
+	if pool.NameColor != logging.Blue || pool.TextColor != logging.Green {
+		t.Error("Expected the parent's colors")
+	}
+	registered := logger.GetSystemModule("Database.Pool")
+	if registered == nil || logger.GetSystemModule("Database").NewChild("Pool") != registered {
+		t.Error("Expected the child to be registered under its path")
+	}
+	if pool.Parent() == nil || pool.Parent().ModuleName != "Database" {
+		t.Error("Expected Database as parent")
+	}
+}
+
+func TestChildModuleKeepsHandleFieldsOut(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+	db := logger.NewSystemModuleLogger("Database", "", "")
+
+	db.With(logging.String("req", "42")).NewChild("Pool").Info("first")
+	db.NewChild("Pool").Info("second")
+	logger.GetSystemModule("Database.Pool").Info("third")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], " req=42") || strings.Contains(lines[1]+lines[2], "req=") {
+		t.Errorf("Expected the With fields on the first handle only, got %q", buf.String())
+	}
+	if logger.GetSystemModule("Database.Pool").Parent() != logger.GetSystemModule("Database") {
+		t.Error("Expected the registered Database module as parent")
+	}
+}
+
+func TestChildModuleLevelInheritance(t *testing.T) {
+	logger := logging.NewLogger(log.New(&bytes.Buffer{}, "", 0), logging.INFO)
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	pool := db.NewChild("Pool")
+	conn := pool.NewChild("Conn")
+	migrations := db.NewChild("Migrations")
+
+	db.SetLogLevel(logging.DEBUG)
+	if conn.GetLogLevel() != logging.DEBUG || migrations.GetLogLevel() != logging.DEBUG {
+		t.Error("Expected descendants to follow Database")
+	}
+
+	pool.SetLogLevel(logging.ERROR)
+	db.SetLogLevel(logging.WARN)
+	if conn.GetLogLevel() != logging.ERROR {
+		t.Errorf("Expected Conn to follow Pool's own level, got %v", conn.GetLogLevel())
+	}
+	if migrations.GetLogLevel() != logging.WARN {
+		t.Errorf("Expected Migrations to follow Database, got %v", migrations.GetLogLevel())
+	}
+
+	pool.ResetLogLevel()
+	db.ResetLogLevel()
+	logger.SetLogLevel(logging.FAIL)
+	if conn.GetLogLevel() != logging.FAIL {
+		t.Errorf("Expected Conn to follow the Logger, got %v", conn.GetLogLevel())
+	}
+}
+
+func TestChildModuleLevelSpec(t *testing.T) {
+	logger := logging.NewLogger(log.New(&bytes.Buffer{}, "", 0), logging.INFO)
+	if err := logger.SetLevelSpec("Database=debug,Database.Pool=error"); err != nil {
+		t.Fatal(err)
+	}
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	if db.NewChild("Pool").GetLogLevel() != logging.ERROR {
+		t.Error("Expected the spec level for Database.Pool")
+	}
+	if db.NewChild("Migrations").GetLogLevel() != logging.DEBUG {
+		t.Error("Expected Database.Migrations to inherit DEBUG")
+	}
+}
+
+func TestSinkIncludesChildModules(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{Include: []string{"Database"}, Exclude: []string{"Database.Migrations"}})
+
+	db := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	db.Info("db")
+	db.NewChild("Pool").Info("pool")
+	db.NewChild("Migrations").Info("migrations")
+	logger.NewSystemModuleLogger("DatabaseTools", logging.Blue, logging.Green).Info("tools")
+
+	got := strings.Join(sink.messages(), ",")
+	if got != "db,pool" {
+		t.Errorf("Expected db,pool, got %s", got)
+	}
+}
@@ -0,0 +1,340 @@
+package otlp_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/errorhandling"
+	"github.com/Mr-Comand/goLogging/logging/logtest"
+	"github.com/Mr-Comand/goLogging/logging/otlp"
+)
+
+// collector stands in for an OpenTelemetry collector.
+type collector struct {
+	mu          sync.Mutex
+	contentType string
+	bodies      [][]byte
+}
+
+func newCollector(t *testing.T) (*collector, string) {
+	c := &collector{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		c.mu.Lock()
+		c.contentType = r.Header.Get("Content-Type")
+		c.bodies = append(c.bodies, body)
+		c.mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return c, srv.URL + "/v1/logs"
+}
+
+func (c *collector) body(t *testing.T) []byte {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.bodies) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(c.bodies))
+	}
+	return c.bodies[0]
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue"`
+	BoolValue   *bool    `json:"boolValue"`
+	IntValue    *string  `json:"intValue"`
+	DoubleValue *float64 `json:"doubleValue"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type logRecord struct {
+	TimeUnixNano   string     `json:"timeUnixNano"`
+	SeverityNumber int        `json:"severityNumber"`
+	SeverityText   string     `json:"severityText"`
+	Body           anyValue   `json:"body"`
+	Attributes     []keyValue `json:"attributes"`
+	TraceID        string     `json:"traceId"`
+	SpanID         string     `json:"spanId"`
+}
+
+type exportRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []keyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []logRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+func attribute(attributes []keyValue, key string) *anyValue {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return &kv.Value
+		}
+	}
+	return nil
+}
+
+func TestJSONExport(t *testing.T) {
+	c, endpoint := newCollector(t)
+	sink, err := otlp.New(otlp.Options{
+		Endpoint:           endpoint,
+		ServiceName:        "shop",
+		ResourceAttributes: map[string]string{"deployment.environment": "test"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.NewLogger(nil, logging.TRACE)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	ctx := logging.ContextWithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	ctx = logging.ContextWithSpanID(ctx, "00f067aa0ba902b7")
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).
+		WarnCtx(ctx, "slow query")
+	logger.InfoW("order placed", logging.Int("items", 3), logging.Bool("paid", true), logging.Any("total", 9.5))
+	logger.Printf("plain")
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.contentType != "application/json" {
+		t.Errorf("Unexpected content type %q", c.contentType)
+	}
+	var req exportRequest
+	if err := json.Unmarshal(c.body(t), &req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.ResourceLogs) != 1 {
+		t.Fatalf("Expected 1 resource, got %d", len(req.ResourceLogs))
+	}
+	rl := req.ResourceLogs[0]
+	if v := attribute(rl.Resource.Attributes, "service.name"); v == nil || *v.StringValue != "shop" {
+		t.Errorf("Expected service.name=shop, got %v", rl.Resource.Attributes)
+	}
+	if v := attribute(rl.Resource.Attributes, "deployment.environment"); v == nil || *v.StringValue != "test" {
+		t.Errorf("Expected the resource attributes, got %v", rl.Resource.Attributes)
+	}
+	if len(rl.ScopeLogs) != 2 || rl.ScopeLogs[0].Scope.Name != "Database" || rl.ScopeLogs[1].Scope.Name != "General" {
+		t.Fatalf("Expected one scope per module, got %+v", rl.ScopeLogs)
+	}
+
+	slow := rl.ScopeLogs[0].LogRecords[0]
+	if slow.SeverityNumber != 13 || slow.SeverityText != "WARN" || *slow.Body.StringValue != "slow query" {
+		t.Errorf("Unexpected record %+v", slow)
+	}
+	if slow.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || slow.SpanID != "00f067aa0ba902b7" {
+		t.Errorf("Expected the trace context, got %q %q", slow.TraceID, slow.SpanID)
+	}
+	if attribute(slow.Attributes, logging.TraceIDKey) != nil || attribute(slow.Attributes, logging.SpanIDKey) != nil {
+		t.Errorf("Expected the IDs not to be repeated as attributes, got %v", slow.Attributes)
+	}
+	if slow.TimeUnixNano == "" || slow.TimeUnixNano == "0" {
+		t.Errorf("Expected a timestamp, got %q", slow.TimeUnixNano)
+	}
+
+	order := rl.ScopeLogs[1].LogRecords[0]
+	if order.SeverityNumber != 9 || order.TraceID != "" {
+		t.Errorf("Unexpected record %+v", order)
+	}
+	if v := attribute(order.Attributes, "items"); v == nil || v.IntValue == nil || *v.IntValue != "3" {
+		t.Errorf("Expected items as intValue, got %v", order.Attributes)
+	}
+	if v := attribute(order.Attributes, "paid"); v == nil || v.BoolValue == nil || !*v.BoolValue {
+		t.Errorf("Expected paid as boolValue, got %v", order.Attributes)
+	}
+	if v := attribute(order.Attributes, "total"); v == nil || v.DoubleValue == nil || *v.DoubleValue != 9.5 {
+		t.Errorf("Expected total as doubleValue, got %v", order.Attributes)
+	}
+	if plain := rl.ScopeLogs[1].LogRecords[1]; plain.SeverityNumber != 0 || plain.SeverityText != "" {
+		t.Errorf("Expected no severity for NONE, got %d %q", plain.SeverityNumber, plain.SeverityText)
+	}
+}
+
+func TestSeverityNumbers(t *testing.T) {
+	expected := map[logging.LogLevel]int{
+		logging.TRACE: 1, logging.DEBUG: 5, logging.INFO: 9, logging.NOTICE: 10,
+		logging.WARN: 13, logging.ERROR: 17, logging.FAIL: 21, logging.NONE: 0,
+	}
+	for level, number := range expected {
+		if n := otlp.SeverityNumber(level); n != number {
+			t.Errorf("SeverityNumber(%s) = %d, expected %d", level, n, number)
+		}
+	}
+}
+
+func TestCustomErrorTraceID(t *testing.T) {
+	logger, recorder := logtest.New()
+	source := &errorhandling.ErrorSource{Name: "Orders", SML: logger.NewSystemModuleLogger("Orders", "", "")}
+	preset := errorhandling.CustomErrorPreset{Code: 500, LogMessage: "payment failed", Source: source, Level: errorhandling.ErrorFail}
+
+	customErr := preset.New().Log()
+	customErr2 := preset.New()
+	customErr2.TraceId = "[TraceId failed]"
+	customErr2.Log()
+
+	body, err := otlp.Format{ServiceName: "shop"}.Encode(recorder.Entries())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req exportRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	logged := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(logged) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(logged))
+	}
+	if logged[0].TraceID != strings.Repeat("0", 16)+customErr.TraceId || logged[0].SeverityNumber != 21 {
+		t.Errorf("Expected the padded trace ID %q at FATAL, got %q at %d", customErr.TraceId, logged[0].TraceID, logged[0].SeverityNumber)
+	}
+	// IDs that are not hex stay an attribute
+	if logged[1].TraceID != "" {
+		t.Errorf("Expected no trace context, got %q", logged[1].TraceID)
+	}
+	if v := attribute(logged[1].Attributes, logging.TraceIDKey); v == nil || *v.StringValue != "[TraceId failed]" {
+		t.Errorf("Expected the trace ID as attribute, got %v", logged[1].Attributes)
+	}
+}
+
+// protoFields decodes one protobuf message into its fields by number.
+// Varint and fixed64 values are returned as uint64, length-delimited values
+// as []byte.
+func protoFields(t *testing.T, b []byte) map[int][]any {
+	t.Helper()
+	fields := map[int][]any{}
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			t.Fatal("Invalid tag")
+		}
+		b = b[n:]
+		field := int(key >> 3)
+		switch key & 7 {
+		case 0:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				t.Fatal("Invalid varint")
+			}
+			fields[field] = append(fields[field], v)
+			b = b[n:]
+		case 1:
+			fields[field] = append(fields[field], binary.LittleEndian.Uint64(b))
+			b = b[8:]
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || int(l) > len(b)-n {
+				t.Fatal("Invalid length")
+			}
+			fields[field] = append(fields[field], b[n:n+int(l)])
+			b = b[n+int(l):]
+		default:
+			t.Fatalf("Unexpected wire type %d", key&7)
+		}
+	}
+	return fields
+}
+
+func TestProtobufExport(t *testing.T) {
+	c, endpoint := newCollector(t)
+	sink, err := otlp.New(otlp.Options{Endpoint: endpoint, Encoding: otlp.Protobuf, ServiceName: "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.NewLogger(nil, logging.TRACE)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	ctx := logging.ContextWithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	logger.With(logging.Any("amount", 12.5), logging.Int("attempt", 2)).ErrorCtx(ctx, "charge failed")
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.contentType != "application/x-protobuf" {
+		t.Errorf("Unexpected content type %q", c.contentType)
+	}
+	request := protoFields(t, c.body(t))
+	resourceLogs := protoFields(t, request[1][0].([]byte))
+	resource := protoFields(t, resourceLogs[1][0].([]byte))
+	serviceName := protoFields(t, resource[1][0].([]byte))
+	if string(serviceName[1][0].([]byte)) != "service.name" || string(protoFields(t, serviceName[2][0].([]byte))[1][0].([]byte)) != "shop" {
+		t.Errorf("Expected service.name=shop, got %v", serviceName)
+	}
+	scopeLogs := protoFields(t, resourceLogs[2][0].([]byte))
+	if name := protoFields(t, scopeLogs[1][0].([]byte))[1][0].([]byte); string(name) != "General" {
+		t.Errorf("Expected the scope General, got %q", name)
+	}
+	record := protoFields(t, scopeLogs[2][0].([]byte))
+	if record[2][0].(uint64) != 17 || string(record[3][0].([]byte)) != "ERROR" {
+		t.Errorf("Unexpected severity %v %q", record[2], record[3])
+	}
+	if body := protoFields(t, record[5][0].([]byte)); string(body[1][0].([]byte)) != "charge failed" {
+		t.Errorf("Unexpected body %q", body[1][0])
+	}
+	if traceID := record[9][0].([]byte); len(traceID) != 16 || traceID[0] != 0x4b || traceID[15] != 0x36 {
+		t.Errorf("Unexpected trace ID %x", traceID)
+	}
+	if _, ok := record[10]; ok {
+		t.Error("Expected no span ID")
+	}
+	if record[1][0].(uint64) == 0 || record[11][0].(uint64) == 0 {
+		t.Error("Expected timestamps")
+	}
+
+	attributes := map[string]map[int][]any{}
+	for _, raw := range record[6] {
+		kv := protoFields(t, raw.([]byte))
+		attributes[string(kv[1][0].([]byte))] = protoFields(t, kv[2][0].([]byte))
+	}
+	if v := attributes["amount"][4]; len(v) != 1 || math.Float64frombits(v[0].(uint64)) != 12.5 {
+		t.Errorf("Expected amount as double, got %v", attributes["amount"])
+	}
+	if v := attributes["attempt"][3]; len(v) != 1 || v[0].(uint64) != 2 {
+		t.Errorf("Expected attempt as int, got %v", attributes["attempt"])
+	}
+}
+
+func TestEndpointFromEnvironment(t *testing.T) {
+	c, endpoint := newCollector(t)
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", strings.TrimSuffix(endpoint, "/v1/logs")+"/")
+	t.Setenv("OTEL_SERVICE_NAME", "from-env")
+	sink, err := otlp.New(otlp.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(&logging.Entry{Level: logging.INFO, Message: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	var req exportRequest
+	if err := json.Unmarshal(c.body(t), &req); err != nil {
+		t.Fatal(err)
+	}
+	if v := attribute(req.ResourceLogs[0].Resource.Attributes, "service.name"); v == nil || *v.StringValue != "from-env" {
+		t.Errorf("Expected service.name from OTEL_SERVICE_NAME, got %v", req.ResourceLogs[0].Resource.Attributes)
+	}
+}
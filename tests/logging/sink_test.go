@@ -0,0 +1,147 @@
+package logging_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	entries []logging.Entry
+}
+
+func (s *recordingSink) Write(e *logging.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, *e)
+	return nil
+}
+
+func (s *recordingSink) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, e := range s.entries {
+		out = append(out, e.Message)
+	}
+	return out
+}
+
+type countingStringer struct{ calls *int }
+
+func (c countingStringer) String() string {
+	*c.calls++
+	return "x"
+}
+
+func TestSinksWithLevels(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	var stdout, file, stderr bytes.Buffer
+	logger.AddSink(logging.NewWriterSink(&stdout, logging.TextEncoder{DisableTextModifier: true}), logging.SinkOptions{Level: logging.INFO})
+	logger.AddSink(logging.NewWriterSink(&file, logging.LogfmtEncoder{}), logging.SinkOptions{Level: logging.DEBUG})
+	logger.AddSink(logging.NewWriterSink(&stderr, logging.TextEncoder{DisableTextModifier: true}), logging.SinkOptions{Level: logging.ERROR})
+
+	logger.Debug("d")
+	logger.Info("i")
+	logger.Error("e")
+
+	if stdout.String() != "[INFO]\t[General]\ti\n[ERROR]\t[General]\te\n" {
+		t.Errorf("Unexpected stdout %q", stdout.String())
+	}
+	if strings.Count(file.String(), "\n") != 3 || !strings.Contains(file.String(), "level=debug") {
+		t.Errorf("File sink should receive every entry, got %q", file.String())
+	}
+	if stderr.String() != "[ERROR]\t[General]\te\n" {
+		t.Errorf("Unexpected stderr %q", stderr.String())
+	}
+}
+
+func TestSinkModuleRouting(t *testing.T) {
+	var primary bytes.Buffer
+	logger := logging.NewLogger(log.New(&primary, "", 0), logging.DEBUG)
+	db := &recordingSink{}
+	rest := &recordingSink{}
+	logger.AddSink(db, logging.SinkOptions{Include: []string{"Database"}})
+	logger.AddSink(rest, logging.SinkOptions{Exclude: []string{"Database"}})
+
+	logger.NewSystemModuleLogger("Database", "", "").Info("query")
+	logger.NewSystemModuleLogger("HTTP", "", "").Info("request")
+	logger.Info("general")
+
+	if got := db.messages(); len(got) != 1 || got[0] != "query" {
+		t.Errorf("Database sink got %v", got)
+	}
+	if got := rest.messages(); len(got) != 2 || got[0] != "request" || got[1] != "general" {
+		t.Errorf("Other sink got %v", got)
+	}
+	if strings.Count(primary.String(), "\n") != 3 {
+		t.Errorf("Primary output should receive every entry, got %q", primary.String())
+	}
+}
+
+func TestLevelCheckShortCircuitsWithoutAcceptingSink(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{Level: logging.WARN})
+
+	calls := 0
+	logger.DebugF("%s", countingStringer{&calls})
+	logger.InfoF("%s", countingStringer{&calls})
+	if calls != 0 {
+		t.Errorf("Message was formatted %d times although no sink accepts it", calls)
+	}
+
+	logger.WarnF("%s", countingStringer{&calls})
+	if calls != 1 || len(sink.messages()) != 1 {
+		t.Errorf("Expected the warning to be formatted and written once, got %d", calls)
+	}
+
+	// A DEBUG sink for one module only enables that module
+	logger.AddSink(&recordingSink{}, logging.SinkOptions{Level: logging.DEBUG, Include: []string{"Database"}})
+	logger.DebugF("%s", countingStringer{&calls})
+	if calls != 1 {
+		t.Error("General debug entries should still be skipped")
+	}
+	logger.NewSystemModuleLogger("Database", "", "").DebugF("%s", countingStringer{&calls})
+	if calls != 2 {
+		t.Error("Database debug entries should be written")
+	}
+}
+
+func TestSinkLevelIsIndependentOfLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.DisableTextModifier = true
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{Level: logging.DEBUG})
+	sml := logger.NewSystemModuleLogger("Database", "", "")
+	sml.SetLogLevel(logging.ERROR)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	sml.Warn("module warn")
+
+	if got := sink.messages(); strings.Join(got, ",") != "debug,info,module warn" {
+		t.Errorf("Expected the sink to receive every entry from DEBUG, got %v", got)
+	}
+	if got := buf.String(); got != "[INFO]\t[General]\tinfo\n" {
+		t.Errorf("Expected the Logger and module levels to apply to the log.Logger, got %q", got)
+	}
+}
+
+func TestRemoveSink(t *testing.T) {
+	logger := logging.NewLogger(nil, logging.INFO)
+	sink := &recordingSink{}
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.Info("one")
+	logger.RemoveSink(sink)
+	logger.Info("two")
+	if got := sink.messages(); len(got) != 1 {
+		t.Errorf("Expected one entry, got %v", got)
+	}
+}
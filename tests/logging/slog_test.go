@@ -0,0 +1,142 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestSlogHandlerConformance(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.SetEncoder(logging.JSONEncoder{})
+
+	results := func() []map[string]any {
+		var ms []map[string]any
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			var m map[string]any
+			if err := json.Unmarshal(line, &m); err != nil {
+				t.Fatalf("Invalid JSON line %q: %v", line, err)
+			}
+			ms = append(ms, m)
+		}
+		return ms
+	}
+	if err := slogtest.TestHandler(logger.Handler(), results); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSlogThroughModuleLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.DEBUG)
+	logger.DisableTextModifier = true
+	sml := logger.NewSystemModuleLogger("HTTP", logging.Blue, logging.Green)
+	sml.SetLogLevel(logging.WARN)
+
+	slogger := slog.New(sml.Handler())
+	slogger.Info("ignored")
+	slogger.WithGroup("req").Warn("slow request", "path", "/api", "status", 200)
+
+	output := buf.String()
+	if strings.Contains(output, "ignored") {
+		t.Errorf("Entries below the module level must be dropped, got: %q", output)
+	}
+	if !strings.HasPrefix(output, "[WARN]\t[HTTP]\tslow request") ||
+		!strings.HasSuffix(output, " req.path=/api req.status=200\n") {
+		t.Errorf("Unexpected output %q", output)
+	}
+}
+
+func TestLoggerWritesThroughSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := logging.NewSlogLogger(handler, logging.DEBUG)
+
+	sml := logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green)
+	sml.ErrorW("query failed", logging.Group("query", logging.String("table", "users"), logging.Int("rows", 2)))
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	if got["level"] != "ERROR" || got["msg"] != "query failed" || got["module"] != "Database" {
+		t.Errorf("Unexpected record %v", got)
+	}
+	query, _ := got["query"].(map[string]any)
+	if query["table"] != "users" || query["rows"] != float64(2) {
+		t.Errorf("Expected nested group, got %v", got["query"])
+	}
+}
+
+// ctxKey is the context key checked by contextHandler.
+type ctxKey struct{}
+
+// contextHandler records the context value under ctxKey of every record.
+type contextHandler struct {
+	slog.Handler
+	values *[]any
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	*h.values = append(*h.values, ctx.Value(ctxKey{}))
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestLoggerPassesSourceAndContextToSlog(t *testing.T) {
+	var buf bytes.Buffer
+	var values []any
+	handler := contextHandler{slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true}), &values}
+	logger := logging.NewSlogLogger(handler, logging.DEBUG)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+	want := line() + 1
+	logger.InfoCtx(ctx, "with context")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	source, _ := got["source"].(map[string]any)
+	if file, _ := source["file"].(string); !strings.HasSuffix(file, "slog_test.go") || source["line"] != float64(want) {
+		t.Errorf("Expected the source slog_test.go:%d, got %v", want, got["source"])
+	}
+	if len(values) != 1 || values[0] != "request" {
+		t.Errorf("Expected the context of the call, got %v", values)
+	}
+}
+
+func TestSlogLevelRoundTrip(t *testing.T) {
+	for _, level := range []logging.LogLevel{logging.TRACE, logging.DEBUG, logging.INFO, logging.NOTICE, logging.WARN, logging.ERROR, logging.FAIL} {
+		if got := logging.FromSlogLevel(logging.SlogLevel(level)); got != level {
+			t.Errorf("Level %d came back as %d", level, got)
+		}
+	}
+	if logging.FromSlogLevel(slog.LevelInfo+1) != logging.INFO || logging.FromSlogLevel(slog.LevelWarn+1) != logging.WARN {
+		t.Error("Custom slog levels should round down")
+	}
+}
+
+func TestSlogRoundTripThroughBothBridges(t *testing.T) {
+	var buf bytes.Buffer
+	inner := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := logging.NewSlogLogger(inner, logging.DEBUG)
+
+	slog.New(logger.Handler()).With("a", 1).WithGroup("g").Warn("m", "b", "x")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	g, _ := got["g"].(map[string]any)
+	if got["level"] != "WARN" || got["a"] != float64(1) || g["b"] != "x" {
+		t.Errorf("Unexpected record %v", got)
+	}
+}
@@ -0,0 +1,277 @@
+package syslog_test
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"math/big"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+	"github.com/Mr-Comand/goLogging/logging/syslog"
+)
+
+func readPacket(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(buf[:n])
+}
+
+// readOctetCounted reads one "LEN SP MSG" frame.
+func readOctetCounted(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	length, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(length))
+	if err != nil {
+		t.Fatalf("Invalid frame length %q", length)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		t.Fatal(err)
+	}
+	return string(msg)
+}
+
+func TestRFC5424OverUDP(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	sink, err := syslog.New(syslog.Options{Network: "udp", Address: server.LocalAddr().String(), Hostname: "web-1", AppName: "shop", Facility: syslog.Local0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	logger.NewSystemModuleLogger("Database", logging.Blue, logging.Green).
+		ErrorW("query failed", logging.String("table", `us"e]rs`), logging.Group("db", logging.Int("pool", 2)))
+
+	msg := readPacket(t, server)
+	// Local0 (16) * 8 + err (3) = 131
+	pattern := `^<131>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}\S+ web-1 shop \d+ Database \[fields@32473 table="us\\"e\\]rs" db\.pool="2"\] query failed$`
+	if !regexp.MustCompile(pattern).MatchString(msg) {
+		t.Errorf("Unexpected message %q", msg)
+	}
+
+	logger.Info("no fields")
+	msg = readPacket(t, server)
+	if !regexp.MustCompile(`^<134>1 \S+ web-1 shop \d+ - - no fields$`).MatchString(msg) {
+		t.Errorf("Unexpected message %q", msg)
+	}
+}
+
+func TestSeverities(t *testing.T) {
+	want := map[logging.LogLevel]syslog.Severity{
+		logging.TRACE:  syslog.Debug,
+		logging.DEBUG:  syslog.Debug,
+		logging.INFO:   syslog.Informational,
+		logging.NOTICE: syslog.Notice,
+		logging.WARN:   syslog.Warning,
+		logging.ERROR:  syslog.Err,
+		logging.FAIL:   syslog.Critical,
+	}
+	for level, severity := range want {
+		if got := syslog.SeverityOf(level); got != severity {
+			t.Errorf("%v: expected %d, got %d", level, severity, got)
+		}
+	}
+}
+
+func TestRFC3164OverUnixgram(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	server, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+	defer server.Close()
+
+	sink, err := syslog.New(syslog.Options{Network: "unixgram", Address: path, Format: syslog.RFC3164, Hostname: "web-1", AppName: "shop", ModuleAsAppName: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	logger.NewSystemModuleLogger("HTTP", logging.Blue, logging.Green).WarnW("slow request", logging.String("path", "/a b"))
+	msg := readPacket(t, server)
+	if !regexp.MustCompile(`^<12>\w{3} [ \d]\d \d\d:\d\d:\d\d web-1 HTTP\[\d+\]: slow request path="/a b"$`).MatchString(msg) {
+		t.Errorf("Unexpected message %q", msg)
+	}
+}
+
+func TestNonTransparentEscapesNewlines(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	sink, err := syslog.New(syslog.Options{Network: "tcp", Address: ln.Addr().String(), AppName: "shop", Framing: syslog.NonTransparent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	logger.Error("panic: boom\ngoroutine 1")
+	logger.Info("next")
+	r := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if msg, err := r.ReadString('\n'); err != nil || !strings.HasSuffix(msg, " panic: boom#012goroutine 1\n") {
+		t.Errorf("Unexpected message %q: %v", msg, err)
+	}
+	if msg, err := r.ReadString('\n'); err != nil || !strings.HasSuffix(msg, " next\n") {
+		t.Errorf("Unexpected message %q: %v", msg, err)
+	}
+}
+
+func TestOctetCountingOverTCPAndReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	conns := make(chan net.Conn, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+		}
+	}()
+
+	sink, err := syslog.New(syslog.Options{Network: "tcp", Address: ln.Addr().String(), AppName: "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	logger.AddSink(sink, logging.SinkOptions{})
+
+	first := <-conns
+	logger.Info("first one")
+	logger.Info("second")
+	r := bufio.NewReader(first)
+	if msg := readOctetCounted(t, r); !strings.HasSuffix(msg, " first one") {
+		t.Errorf("Unexpected message %q", msg)
+	}
+	if msg := readOctetCounted(t, r); !strings.HasSuffix(msg, " second") {
+		t.Errorf("Unexpected message %q", msg)
+	}
+
+	// Drop the connection; the sink notices on a later write and reconnects
+	first.Close()
+	deadline := time.After(5 * time.Second)
+	for {
+		logger.Info("after reconnect")
+		select {
+		case second := <-conns:
+			defer second.Close()
+			msg := readOctetCounted(t, bufio.NewReader(second))
+			if !strings.HasSuffix(msg, " after reconnect") {
+				t.Errorf("Unexpected message %q", msg)
+			}
+			return
+		case <-deadline:
+			t.Fatal("The sink did not reconnect")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func TestTLS(t *testing.T) {
+	cert := selfSignedCert(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		length, _ := r.ReadString(' ')
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		msg := make([]byte, n)
+		_, _ = io.ReadFull(r, msg)
+		received <- string(msg)
+	}()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert.Leaf)
+	sink, err := syslog.New(syslog.Options{Network: "tls", Address: ln.Addr().String(), TLSConfig: &tls.Config{RootCAs: pool, ServerName: "localhost"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	logger := logging.NewLogger(nil, logging.DEBUG)
+	logger.AddSink(sink, logging.SinkOptions{})
+	logger.Warn("over tls")
+
+	select {
+	case msg := <-received:
+		if !strings.HasPrefix(msg, "<12>1 ") || !strings.HasSuffix(msg, " over tls") {
+			t.Errorf("Unexpected message %q", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No message received")
+	}
+}
+
+func selfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
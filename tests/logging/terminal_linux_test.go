@@ -0,0 +1,75 @@
+//go:build linux
+
+package logging_test
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+// openPty opens a pseudo-terminal and returns its controlling and terminal
+// side.
+func openPty(t *testing.T) (*os.File, *os.File) {
+	t.Helper()
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal available: %v", err)
+	}
+	t.Cleanup(func() { ptmx.Close() })
+
+	var unlock int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, ptmx.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 {
+		t.Skipf("unlockpt: %v", errno)
+	}
+	var n uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, ptmx.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 {
+		t.Skipf("ptsname: %v", errno)
+	}
+	pts, err := os.OpenFile("/dev/pts/"+strconv.Itoa(int(n)), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("open pts: %v", err)
+	}
+	t.Cleanup(func() { pts.Close() })
+	return ptmx, pts
+}
+
+func TestColorAutoTerminal(t *testing.T) {
+	clearColorEnv(t)
+	ptmx, pts := openPty(t)
+	if !logging.IsTerminal(pts) {
+		t.Fatal("Expected the pty to be detected as terminal")
+	}
+
+	logger := logging.NewLogger(log.New(pts, "", 0), logging.INFO)
+	logger.Info("to a terminal")
+	line, err := bufio.NewReader(ptmx).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasColor(line) {
+		t.Errorf("Expected colors on a terminal, got %q", line)
+	}
+}
+
+func TestColorAutoTerminalNoColor(t *testing.T) {
+	clearColorEnv(t)
+	t.Setenv("NO_COLOR", "1")
+	ptmx, pts := openPty(t)
+
+	logger := logging.NewLogger(log.New(pts, "", 0), logging.INFO)
+	logger.Info("to a terminal")
+	line, err := bufio.NewReader(ptmx).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasColor(line) {
+		t.Errorf("Expected NO_COLOR to disable colors, got %q", line)
+	}
+}
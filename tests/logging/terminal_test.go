@@ -0,0 +1,133 @@
+package logging_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+// clearColorEnv unsets the variables ColorAuto looks at for the test.
+func clearColorEnv(t *testing.T) {
+	for _, name := range []string{"NO_COLOR", "FORCE_COLOR", "TERM"} {
+		if value, ok := os.LookupEnv(name); ok {
+			os.Unsetenv(name)
+			t.Cleanup(func() { os.Setenv(name, value) })
+		}
+	}
+}
+
+func hasColor(s string) bool {
+	return strings.Contains(s, "\033[")
+}
+
+func TestColorModes(t *testing.T) {
+	clearColorEnv(t)
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+
+	logger.SetColorMode(logging.ColorNever)
+	logger.Info("never")
+	if hasColor(buf.String()) {
+		t.Errorf("ColorNever printed colors: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetColorMode(logging.ColorAlways)
+	logger.Info("always")
+	if !hasColor(buf.String()) {
+		t.Errorf("ColorAlways printed no colors: %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.DisableTextModifier = true
+	logger.Info("disabled")
+	if hasColor(buf.String()) {
+		t.Errorf("DisableTextModifier should win over ColorAlways: %q", buf.String())
+	}
+}
+
+func TestColorAutoFile(t *testing.T) {
+	clearColorEnv(t)
+	f, err := os.CreateTemp(t.TempDir(), "log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if logging.IsTerminal(f) {
+		t.Fatal("A regular file is not a terminal")
+	}
+
+	logger := logging.NewLogger(log.New(f, "", 0), logging.INFO)
+	logger.Info("to a file")
+	if out := readFile(t, f.Name()); hasColor(out) {
+		t.Errorf("Expected no colors in a file, got %q", out)
+	}
+
+	// The environment is cached until the color mode is set again
+	t.Setenv("FORCE_COLOR", "1")
+	logger.Info("cached")
+	if out := readFile(t, f.Name()); hasColor(out) {
+		t.Errorf("Expected the environment to be read once, got %q", out)
+	}
+	logger.SetColorMode(logging.ColorAuto)
+	logger.Info("forced")
+	if out := readFile(t, f.Name()); !hasColor(out) {
+		t.Errorf("Expected FORCE_COLOR to enable colors, got %q", out)
+	}
+}
+
+func TestColorAutoEnv(t *testing.T) {
+	cases := []struct {
+		env     map[string]string
+		colored bool
+	}{
+		{map[string]string{}, true},
+		{map[string]string{"NO_COLOR": "1"}, false},
+		{map[string]string{"NO_COLOR": ""}, true},
+		{map[string]string{"TERM": "dumb"}, false},
+		{map[string]string{"TERM": "dumb", "FORCE_COLOR": "1"}, true},
+		{map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "true"}, true},
+		{map[string]string{"FORCE_COLOR": "0"}, false},
+	}
+	for _, c := range cases {
+		clearColorEnv(t)
+		for k, v := range c.env {
+			t.Setenv(k, v)
+		}
+		var buf bytes.Buffer
+		logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+		logger.Info("env")
+		if hasColor(buf.String()) != c.colored {
+			t.Errorf("%v: expected colored=%v, got %q", c.env, c.colored, buf.String())
+		}
+		for k := range c.env {
+			os.Unsetenv(k)
+		}
+	}
+}
+
+func TestColorModeFlag(t *testing.T) {
+	var mode logging.ColorMode
+	if err := mode.Set("Never"); err != nil || mode != logging.ColorNever {
+		t.Errorf("Expected ColorNever, got %v, %v", mode, err)
+	}
+	if mode.String() != "never" {
+		t.Errorf("Unexpected String() %q", mode.String())
+	}
+	if err := mode.Set("sometimes"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
+
+func readFile(t *testing.T, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
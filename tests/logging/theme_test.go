@@ -0,0 +1,122 @@
+package logging_test
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mr-Comand/goLogging/logging"
+)
+
+func TestExtendedColors(t *testing.T) {
+	if logging.Color256(208) != "\033[38;5;208m" || logging.BG256(17) != "\033[48;5;17m" {
+		t.Error("Unexpected 256-color modifiers")
+	}
+	if logging.RGB(255, 128, 0) != "\033[38;2;255;128;0m" || logging.BgRGB(0, 0, 0) != "\033[48;2;0;0;0m" {
+		t.Error("Unexpected 24-bit modifiers")
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	cases := []struct {
+		in    logging.TextModifier
+		depth logging.ColorDepth
+		want  logging.TextModifier
+	}{
+		{logging.RGB(255, 128, 0), logging.TrueColor, logging.RGB(255, 128, 0)},
+		{logging.RGB(255, 135, 0), logging.Colors256, logging.Color256(208)},
+		{logging.RGB(128, 128, 128), logging.Colors256, logging.Color256(244)},
+		{logging.RGB(250, 10, 10), logging.Colors16, logging.BrightRed},
+		{logging.BgRGB(0, 0, 230), logging.Colors16, logging.BlueBG},
+		{logging.Color256(196), logging.Colors16, logging.BrightRed},
+		{logging.Color256(208), logging.Colors256, logging.Color256(208)},
+		{logging.Color256(2), logging.Colors16, logging.Green},
+		{logging.Bold + logging.RGB(0, 205, 205), logging.Colors16, logging.Bold + logging.Cyan},
+		{logging.Red + logging.MagentaBG, logging.Colors16, logging.Red + logging.MagentaBG},
+	}
+	for _, c := range cases {
+		if got := c.in.Downsample(c.depth); got != c.want {
+			t.Errorf("%q.Downsample(%d) = %q, want %q", c.in, c.depth, got, c.want)
+		}
+	}
+}
+
+func TestDetectColorDepth(t *testing.T) {
+	clearColorEnv(t)
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm")
+	if logging.DetectColorDepth() != logging.Colors16 {
+		t.Error("Expected 16 colors for xterm")
+	}
+	t.Setenv("TERM", "xterm-256color")
+	if logging.DetectColorDepth() != logging.Colors256 {
+		t.Error("Expected 256 colors for xterm-256color")
+	}
+	t.Setenv("COLORTERM", "truecolor")
+	if logging.DetectColorDepth() != logging.TrueColor {
+		t.Error("Expected true color for COLORTERM=truecolor")
+	}
+	t.Setenv("FORCE_COLOR", "1")
+	if logging.DetectColorDepth() != logging.Colors16 {
+		t.Error("Expected FORCE_COLOR=1 to limit to 16 colors")
+	}
+}
+
+func TestDefaultThemeKeepsOutput(t *testing.T) {
+	entry := &logging.Entry{Level: logging.FAIL, Message: "down"}
+	plain, _ := logging.TextEncoder{}.Encode(entry)
+	themed, _ := logging.TextEncoder{Theme: logging.DefaultTheme()}.Encode(entry)
+	if string(plain) != string(themed) {
+		t.Errorf("Expected the default theme without a Theme, got %q and %q", plain, themed)
+	}
+	if !strings.HasPrefix(string(plain), string(logging.Red+logging.MagentaBG)+"[FAIL]") {
+		t.Errorf("Unexpected FAIL style %q", plain)
+	}
+}
+
+func TestThemeStyles(t *testing.T) {
+	theme := &logging.Theme{
+		Levels:   map[logging.LogLevel]logging.LevelStyle{logging.WARN: {Tag: logging.RGB(255, 135, 0)}},
+		Module:   logging.Bold,
+		Time:     logging.Dim,
+		FieldKey: logging.Underline,
+	}
+	enc := logging.TextEncoder{Theme: theme, TimeFormat: "15:04", ColorDepth: logging.Colors256}
+	line, err := enc.Encode(&logging.Entry{
+		Time:    time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC),
+		Level:   logging.WARN,
+		Message: "slow",
+		Fields:  []logging.Field{logging.Int("ms", 900)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(line)
+	for _, want := range []string{
+		string(logging.Dim) + "12:30" + string(logging.Reset),
+		string(logging.Color256(208)) + "[WARN]",
+		string(logging.Bold) + "\t[General]",
+		string(logging.Underline) + "ms",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Expected %q in %q", want, s)
+		}
+	}
+}
+
+func TestLoggerTheme(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewLogger(log.New(&buf, "", 0), logging.INFO)
+	logger.SetColorMode(logging.ColorAlways)
+	logger.SetTheme(logging.MonochromeTheme())
+
+	logger.ErrorW("failed", logging.String("key", "value"))
+	if !strings.HasPrefix(buf.String(), string(logging.Bold+logging.Underline)+"[ERROR]") {
+		t.Errorf("Expected the monochrome ERROR style, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), string(logging.Cyan)) {
+		t.Errorf("Expected no colors, got %q", buf.String())
+	}
+}